@@ -0,0 +1,157 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type AdmissionHandlerConfig struct {
+	Logger    micrologger.Logger
+	Validator *Validator
+}
+
+// AdmissionHandler is an admission.Handler validating App CRs. Creations
+// are validated with ValidateAppAll and updates with both ValidateAppAll and
+// ValidateAppUpdateAll, so that denials list every failure. Updates of apps
+// being deleted and updates only changing finalizers are allowed without
+// validation, so that finalizers can be removed even when e.g. the catalog
+// of the app is gone. Validation warnings are returned as admission
+// warnings. Serve it with e.g.
+//
+//	mgr.GetWebhookServer().Register("/validate/app", &admission.Webhook{Handler: handler})
+type AdmissionHandler struct {
+	decoder   admission.Decoder
+	logger    micrologger.Logger
+	validator *Validator
+}
+
+func NewAdmissionHandler(config AdmissionHandlerConfig) (*AdmissionHandler, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+	if config.Validator == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Validator must not be empty", config)
+	}
+
+	scheme := runtime.NewScheme()
+	err := v1alpha1.AddToScheme(scheme)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	h := &AdmissionHandler{
+		decoder:   admission.NewDecoder(scheme),
+		logger:    config.Logger,
+		validator: config.Validator,
+	}
+
+	return h, nil
+}
+
+// Handle validates the App of the given admission request. Requests for
+// other kinds are rejected and operations other than create and update are
+// allowed.
+func (h *AdmissionHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Kind.Group != v1alpha1.SchemeGroupVersion.Group || req.Kind.Kind != "App" {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("expected kind %#q but got %#q", "App", req.Kind.Kind))
+	}
+
+	var app v1alpha1.App
+	var allErrs field.ErrorList
+	var warnings []string
+
+	switch req.Operation {
+	case admissionv1.Create:
+		err := h.decoder.Decode(req, &app)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		allErrs, warnings, err = h.validator.ValidateAppAll(ctx, app)
+		if err != nil {
+			return h.errored(ctx, req, err)
+		}
+	case admissionv1.Update:
+		err := h.decoder.Decode(req, &app)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		var currentApp v1alpha1.App
+		err = h.decoder.DecodeRaw(req.OldObject, &currentApp)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		if app.DeletionTimestamp != nil || onlyFinalizersChanged(app, currentApp) {
+			h.logger.Debugf(ctx, "allowed %s of app '%s/%s' without validation as it only changes finalizers or the app is being deleted", req.Operation, req.Namespace, req.Name)
+			return admission.Allowed("")
+		}
+
+		// Updated apps must be valid as a whole too, otherwise e.g. the
+		// app namespace annotation could be added after the creation.
+		allErrs, warnings, err = h.validator.ValidateAppAll(ctx, app)
+		if err != nil {
+			return h.errored(ctx, req, err)
+		}
+
+		updateErrs, updateWarnings, err := h.validator.ValidateAppUpdateAll(ctx, app, currentApp)
+		if err != nil {
+			return h.errored(ctx, req, err)
+		}
+
+		allErrs = append(allErrs, updateErrs...)
+		warnings = append(warnings, updateWarnings...)
+	default:
+		return admission.Allowed("")
+	}
+
+	if len(allErrs) > 0 {
+		h.logger.Debugf(ctx, "denied %s of app '%s/%s': %s", req.Operation, req.Namespace, req.Name, allErrs.ToAggregate().Error())
+
+		status := NewInvalidAppError(app, allErrs).ErrStatus
+
+		response := admission.Response{
+			AdmissionResponse: admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result:  &status,
+			},
+		}
+
+		return response.WithWarnings(warnings...)
+	}
+
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+// onlyFinalizersChanged returns true when the given apps differ in nothing
+// but their finalizers and the metadata maintained by the API server.
+func onlyFinalizersChanged(app, currentApp v1alpha1.App) bool {
+	a := app.DeepCopy()
+	b := currentApp.DeepCopy()
+
+	for _, o := range []*v1alpha1.App{a, b} {
+		o.Finalizers = nil
+		o.ManagedFields = nil
+		o.ResourceVersion = ""
+		o.Generation = 0
+	}
+
+	return equality.Semantic.DeepEqual(a, b)
+}
+
+func (h *AdmissionHandler) errored(ctx context.Context, req admission.Request, err error) admission.Response {
+	h.logger.Errorf(ctx, err, "failed to validate %s of app '%s/%s'", req.Operation, req.Namespace, req.Name)
+
+	return admission.Errored(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,280 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+	"github.com/giantswarm/micrologger/microloggertest"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake" //nolint:staticcheck
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func Test_AdmissionHandler(t *testing.T) {
+	newApp := func(catalog, namespace string) *v1alpha1.App {
+		return &v1alpha1.App{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "App",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kiam",
+				Namespace: "giantswarm",
+				Labels: map[string]string{
+					label.AppOperatorVersion: "0.0.0",
+				},
+			},
+			Spec: v1alpha1.AppSpec{
+				Catalog:   catalog,
+				Name:      "kiam",
+				Namespace: namespace,
+				KubeConfig: v1alpha1.AppSpecKubeConfig{
+					InCluster: true,
+				},
+				Version: "1.4.0",
+			},
+		}
+	}
+
+	appKind := metav1.GroupVersionKind{
+		Group:   v1alpha1.SchemeGroupVersion.Group,
+		Version: v1alpha1.SchemeGroupVersion.Version,
+		Kind:    "App",
+	}
+
+	tests := []struct {
+		name            string
+		kind            metav1.GroupVersionKind
+		operation       admissionv1.Operation
+		obj             *v1alpha1.App
+		oldObj          *v1alpha1.App
+		expectedAllowed bool
+		expectedCode    int32
+		expectedCauses  int
+		expectedWarning string
+	}{
+		{
+			name:            "case 0: valid app is created",
+			kind:            appKind,
+			operation:       admissionv1.Create,
+			obj:             newApp("giantswarm", "kube-system"),
+			expectedAllowed: true,
+			expectedCode:    http.StatusOK,
+			expectedWarning: "appcatalogentry `giantswarm-kiam-1.4.0` not found, metadata constraints of app `kiam` were not validated",
+		},
+		{
+			name:           "case 1: invalid app is denied",
+			kind:           appKind,
+			operation:      admissionv1.Create,
+			obj:            newApp("missing", "kube-system"),
+			expectedCode:   http.StatusUnprocessableEntity,
+			expectedCauses: 1,
+		},
+		{
+			name:           "case 2: changed target namespace is denied",
+			kind:           appKind,
+			operation:      admissionv1.Update,
+			obj:            newApp("giantswarm", "default"),
+			oldObj:         newApp("giantswarm", "kube-system"),
+			expectedCode:   http.StatusUnprocessableEntity,
+			expectedCauses: 1,
+		},
+		{
+			name:            "case 3: deletion is allowed",
+			kind:            appKind,
+			operation:       admissionv1.Delete,
+			oldObj:          newApp("missing", "kube-system"),
+			expectedAllowed: true,
+			expectedCode:    http.StatusOK,
+		},
+		{
+			name:         "case 4: other kinds are rejected",
+			kind:         metav1.GroupVersionKind{Group: v1alpha1.SchemeGroupVersion.Group, Version: v1alpha1.SchemeGroupVersion.Version, Kind: "Catalog"},
+			operation:    admissionv1.Create,
+			obj:          newApp("giantswarm", "kube-system"),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:      "case 5: update adding a mismatched app namespace annotation is denied",
+			kind:      appKind,
+			operation: admissionv1.Update,
+			obj: func() *v1alpha1.App {
+				app := newApp("giantswarm", "kube-system")
+				app.Annotations = map[string]string{annotation.AppNamespace: "kube-system"}
+				return app
+			}(),
+			oldObj:         newApp("giantswarm", "kube-system"),
+			expectedCode:   http.StatusUnprocessableEntity,
+			expectedCauses: 1,
+		},
+		{
+			name:           "case 6: update to a missing catalog is denied",
+			kind:           appKind,
+			operation:      admissionv1.Update,
+			obj:            newApp("missing", "kube-system"),
+			oldObj:         newApp("giantswarm", "kube-system"),
+			expectedCode:   http.StatusUnprocessableEntity,
+			expectedCauses: 1,
+		},
+		{
+			name:      "case 7: removing the finalizer of a deleted app with a missing catalog is allowed",
+			kind:      appKind,
+			operation: admissionv1.Update,
+			obj: func() *v1alpha1.App {
+				app := newApp("missing", "kube-system")
+				app.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				return app
+			}(),
+			oldObj: func() *v1alpha1.App {
+				app := newApp("missing", "kube-system")
+				app.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				app.Finalizers = []string{"operatorkit.giantswarm.io/app-operator-app"}
+				return app
+			}(),
+			expectedAllowed: true,
+			expectedCode:    http.StatusOK,
+		},
+		{
+			name:      "case 8: adding a finalizer to an app with a missing catalog is allowed",
+			kind:      appKind,
+			operation: admissionv1.Update,
+			obj: func() *v1alpha1.App {
+				app := newApp("missing", "kube-system")
+				app.Finalizers = []string{"operatorkit.giantswarm.io/app-operator-app"}
+				return app
+			}(),
+			oldObj:          newApp("missing", "kube-system"),
+			expectedAllowed: true,
+			expectedCode:    http.StatusOK,
+		},
+		{
+			name:      "case 9: update changing more than finalizers of an app with a missing catalog is denied",
+			kind:      appKind,
+			operation: admissionv1.Update,
+			obj: func() *v1alpha1.App {
+				app := newApp("missing", "kube-system")
+				app.Finalizers = []string{"operatorkit.giantswarm.io/app-operator-app"}
+				app.Labels["foo"] = "bar"
+				return app
+			}(),
+			oldObj:         newApp("missing", "kube-system"),
+			expectedCode:   http.StatusUnprocessableEntity,
+			expectedCauses: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = v1alpha1.AddToScheme(scheme)
+
+			fakeCtrlClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(newTestCatalog("giantswarm", "default")).
+				WithIndex(&v1alpha1.App{}, "metadata.name", appNameIndexer).
+				Build()
+
+			validator, err := NewValidator(Config{
+				G8sClient: fakeCtrlClient,
+				K8sClient: clientgofake.NewClientset(),
+				Logger:    microloggertest.New(),
+
+				IsAdmissionController: true,
+				Provider:              "aws",
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			handler, err := NewAdmissionHandler(AdmissionHandlerConfig{
+				Logger:    microloggertest.New(),
+				Validator: validator,
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			server := httptest.NewServer(&admission.Webhook{Handler: handler})
+			defer server.Close()
+
+			review := admissionv1.AdmissionReview{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: admissionv1.SchemeGroupVersion.String(),
+					Kind:       "AdmissionReview",
+				},
+				Request: &admissionv1.AdmissionRequest{
+					UID:       "test-uid",
+					Kind:      tc.kind,
+					Name:      "kiam",
+					Namespace: "giantswarm",
+					Operation: tc.operation,
+					Object:    rawExtension(t, tc.obj),
+					OldObject: rawExtension(t, tc.oldObj),
+				},
+			}
+
+			body, err := json.Marshal(review)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			resp, err := http.Post(server.URL, "application/json", bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+			defer resp.Body.Close()
+
+			var result admissionv1.AdmissionReview
+			err = json.NewDecoder(resp.Body).Decode(&result)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			if result.Response.UID != "test-uid" {
+				t.Fatalf("uid == %#q, want %#q", result.Response.UID, "test-uid")
+			}
+			if result.Response.Allowed != tc.expectedAllowed {
+				t.Fatalf("allowed == %t, want %t", result.Response.Allowed, tc.expectedAllowed)
+			}
+			if result.Response.Result.Code != tc.expectedCode {
+				t.Fatalf("code == %d, want %d", result.Response.Result.Code, tc.expectedCode)
+			}
+
+			var causes int
+			if result.Response.Result.Details != nil {
+				causes = len(result.Response.Result.Details.Causes)
+			}
+			if causes != tc.expectedCauses {
+				t.Fatalf("causes == %d, want %d", causes, tc.expectedCauses)
+			}
+
+			if tc.expectedWarning != "" && !slices.Contains(result.Response.Warnings, tc.expectedWarning) {
+				t.Fatalf("warnings == %v, want %#q", result.Response.Warnings, tc.expectedWarning)
+			}
+		})
+	}
+}
+
+func rawExtension(t *testing.T, obj *v1alpha1.App) runtime.RawExtension {
+	if obj == nil {
+		return runtime.RawExtension{}
+	}
+
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	return runtime.RawExtension{Raw: raw}
+}
@@ -6,12 +6,14 @@ import (
 	"strings"
 
 	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
 	"github.com/giantswarm/k8smetadata/pkg/label"
 	"github.com/giantswarm/microerror"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/giantswarm/app/v8/pkg/key"
@@ -36,74 +38,113 @@ const (
 	nameMaxLength = 53
 )
 
-func (v *Validator) ValidateApp(ctx context.Context, app v1alpha1.App) (bool, error) {
-	var err error
+// appValidator validates a single aspect of an app.
+type appValidator func(ctx context.Context, cr v1alpha1.App) error
 
-	err = v.validateAnnotations(ctx, app)
+func (v *Validator) ValidateApp(ctx context.Context, app v1alpha1.App) (bool, error) {
+	_, err := v.ValidateAppWithWarnings(ctx, app)
 	if err != nil {
 		return false, microerror.Mask(err)
 	}
 
-	err = v.validateCatalog(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
-	}
+	return true, nil
+}
 
-	err = v.validateLabels(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
-	}
+// ValidateAppWithWarnings works like ValidateApp but also returns warnings.
+// Warnings are advisory findings, e.g. a version prefixed with `v`, that do
+// not fail the validation.
+func (v *Validator) ValidateAppWithWarnings(ctx context.Context, app v1alpha1.App) ([]string, error) {
+	ctx, recorder := withWarnings(ctx)
+	ctx = withPolicyVariables(ctx)
 
-	err = v.validateConfig(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
+	for _, validate := range v.appValidators() {
+		err := validate(ctx, app)
+		if err != nil {
+			return recorder.warnings, microerror.Mask(err)
+		}
 	}
 
-	err = v.validateKubeConfig(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
-	}
+	return recorder.warnings, nil
+}
 
-	err = v.validateMetadataConstraints(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
-	}
+// ValidateAppAll works like ValidateAppWithWarnings but runs all validations
+// and returns every failure found together with the path of the failing
+// field. Errors not caused by the app, e.g. failing API calls, stop the
+// validation and are returned as error. The failures can be turned into an
+// admission response with NewInvalidAppError.
+func (v *Validator) ValidateAppAll(ctx context.Context, app v1alpha1.App) (field.ErrorList, []string, error) {
+	ctx, recorder := withWarnings(ctx)
+	ctx = withPolicyVariables(ctx)
 
-	err = v.validateName(ctx, app)
+	allErrs, err := collectFieldErrors(ctx, app, v.appValidators())
 	if err != nil {
-		return false, microerror.Mask(err)
+		return nil, nil, microerror.Mask(err)
 	}
 
-	err = v.validateNamespaceConfig(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
-	}
+	return allErrs, recorder.warnings, nil
+}
 
-	err = v.validateTargetNamespace(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
+func (v *Validator) ValidateAppUpdate(ctx context.Context, app, currentApp v1alpha1.App) (bool, error) {
+	for _, validate := range v.appUpdateValidators(currentApp) {
+		err := validate(ctx, app)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
 	}
 
-	err = v.validateUserConfig(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
-	}
+	return true, nil
+}
 
-	err = v.validateUniqueInClusterAppName(ctx, app)
+// ValidateAppUpdateAll works like ValidateAppUpdate but returns every
+// failure and warning found like ValidateAppAll does.
+func (v *Validator) ValidateAppUpdateAll(ctx context.Context, app, currentApp v1alpha1.App) (field.ErrorList, []string, error) {
+	ctx, recorder := withWarnings(ctx)
+
+	allErrs, err := collectFieldErrors(ctx, app, v.appUpdateValidators(currentApp))
 	if err != nil {
-		return false, microerror.Mask(err)
+		return nil, nil, microerror.Mask(err)
 	}
 
-	return true, nil
+	return allErrs, recorder.warnings, nil
 }
 
-func (v *Validator) ValidateAppUpdate(ctx context.Context, app, currentApp v1alpha1.App) (bool, error) {
-	err := v.validateNamespaceUpdate(ctx, app, currentApp)
-	if err != nil {
-		return false, microerror.Mask(err)
-	}
+// appValidators returns the validators run on apps in the order they are
+// run. Custom rules run last.
+func (v *Validator) appValidators() []appValidator {
+	validators := []appValidator{
+		v.validateAnnotations,
+		v.validateCatalog,
+		v.validateLabels,
+		v.validateConfigMapConfig,
+		v.validateSecretConfig,
+		v.validateKubeConfig,
+		v.validateMetadataConstraints,
+		v.validateName,
+		v.validateNamespaceConfig,
+		v.validateTargetNamespace,
+		v.validateUserConfigMap,
+		v.validateUserSecret,
+		v.validateUniqueInClusterAppName,
+		v.validateVersion,
+	}
+
+	for _, rule := range v.rules {
+		validators = append(validators, func(ctx context.Context, cr v1alpha1.App) error {
+			return v.validateRule(ctx, rule, cr)
+		})
+	}
+
+	return validators
+}
 
-	return true, nil
+// appUpdateValidators returns the validators run on updates of the given
+// current app.
+func (v *Validator) appUpdateValidators(currentApp v1alpha1.App) []appValidator {
+	return []appValidator{
+		func(ctx context.Context, app v1alpha1.App) error {
+			return v.validateNamespaceUpdate(ctx, app, currentApp)
+		},
+	}
 }
 
 // This is for preventing chart-operator to select elevated
@@ -113,19 +154,33 @@ func (v *Validator) ValidateAppUpdate(ctx context.Context, app, currentApp v1alp
 func (v *Validator) validateAnnotations(ctx context.Context, cr v1alpha1.App) error {
 	namespaceAnnotation := key.AppNamespaceAnnotation(cr)
 	if namespaceAnnotation != "" && namespaceAnnotation != cr.Namespace {
-		return microerror.Maskf(validationError, namespaceMismatchTemplate, namespaceAnnotation)
+		return newFieldError(validationError, field.ErrorTypeInvalid, metadataPath.Child("annotations").Key(annotation.AppNamespace), namespaceAnnotation, namespaceMismatchTemplate, namespaceAnnotation)
 	}
 
 	return nil
 }
 
 func (v *Validator) validateCatalog(ctx context.Context, cr v1alpha1.App) error {
-	var err error
-
 	if key.CatalogName(cr) == "" {
 		return nil
 	}
 
+	matchedCatalog, err := v.findCatalog(ctx, cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if matchedCatalog == nil || matchedCatalog.Name == "" {
+		return newFieldError(validationError, field.ErrorTypeNotFound, specPath.Child("catalog"), key.CatalogName(cr), catalogNotFoundTemplate, key.CatalogName(cr))
+	}
+
+	return nil
+}
+
+// findCatalog returns the catalog of the given app or nil if it does not
+// exist. Catalogs without a namespace are looked up in the default and
+// giantswarm namespaces.
+func (v *Validator) findCatalog(ctx context.Context, cr v1alpha1.App) (*v1alpha1.Catalog, error) {
 	var namespaces []string
 	{
 		if key.CatalogNamespace(cr) != "" {
@@ -135,11 +190,9 @@ func (v *Validator) validateCatalog(ctx context.Context, cr v1alpha1.App) error
 		}
 	}
 
-	var matchedCatalog *v1alpha1.Catalog
-
 	for _, ns := range namespaces {
 		var catalog v1alpha1.Catalog
-		err = v.g8sClient.Get(ctx, client.ObjectKey{
+		err := v.g8sClient.Get(ctx, client.ObjectKey{
 			Namespace: ns,
 			Name:      key.CatalogName(cr),
 		}, &catalog)
@@ -147,56 +200,63 @@ func (v *Validator) validateCatalog(ctx context.Context, cr v1alpha1.App) error
 			// no-op
 			continue
 		} else if err != nil {
-			return microerror.Mask(err)
+			return nil, microerror.Mask(err)
 		}
-		matchedCatalog = &catalog
-		break
+		return &catalog, nil
 	}
 
-	if matchedCatalog == nil || matchedCatalog.Name == "" {
-		return microerror.Maskf(validationError, catalogNotFoundTemplate, key.CatalogName(cr))
+	return nil, nil
+}
+
+func (v *Validator) validateConfigMapConfig(ctx context.Context, cr v1alpha1.App) error {
+	if key.AppConfigMapName(cr) == "" {
+		return nil
+	}
+
+	path := specPath.Child("config", "configMap")
+
+	if err := v.validateNameAndNamespaceAreSet(path, key.AppConfigMapName(cr), key.AppConfigMapNamespace(cr), "configmap"); err != nil {
+		return microerror.Mask(err)
+	}
+
+	if v.isAdmissionController {
+		v.logger.Debugf(ctx, "skipping '.spec.config.configMap' validation of app '%s/%s' in admission controllers", cr.Namespace, cr.Name)
+		return nil
+	}
+
+	err := v.validateConfigMapExists(ctx, key.AppConfigMapName(cr), key.AppConfigMapNamespace(cr), "configmap")
+	if apierrors.IsNotFound(err) {
+		// appConfigMapNotFoundError is used rather than a validation error because
+		// during cluster creation there is a short delay while it is generated.
+		return newFieldError(appConfigMapNotFoundError, field.ErrorTypeNotFound, path.Child("name"), key.AppConfigMapName(cr), resourceNotFoundTemplate, "configmap", key.AppConfigMapName(cr), key.AppConfigMapNamespace(cr))
+	} else if err != nil {
+		return microerror.Mask(err)
 	}
 
 	return nil
 }
 
-func (v *Validator) validateConfig(ctx context.Context, cr v1alpha1.App) error {
-	if key.AppConfigMapName(cr) != "" {
-		if err := v.validateNameAndNamespaceAreSet(key.AppConfigMapName(cr), key.AppConfigMapNamespace(cr), "configmap"); err != nil {
-			return microerror.Mask(err)
-		}
+func (v *Validator) validateSecretConfig(ctx context.Context, cr v1alpha1.App) error {
+	if key.AppSecretName(cr) == "" {
+		return nil
+	}
 
-		if v.isAdmissionController {
-			v.logger.Debugf(ctx, "skipping '.spec.config.configMap' validation of app '%s/%s' in admission controllers", cr.Namespace, cr.Name)
-		} else {
-			err := v.validateConfigMapExists(ctx, key.AppConfigMapName(cr), key.AppConfigMapNamespace(cr), "configmap")
-			if apierrors.IsNotFound(err) {
-				// appConfigMapNotFoundError is used rather than a validation error because
-				// during cluster creation there is a short delay while it is generated.
-				return microerror.Maskf(appConfigMapNotFoundError, resourceNotFoundTemplate, "configmap", key.AppConfigMapName(cr), key.AppConfigMapNamespace(cr))
-			} else if err != nil {
-				return microerror.Mask(err)
-			}
-		}
+	path := specPath.Child("config", "secret")
 
+	if err := v.validateNameAndNamespaceAreSet(path, key.AppSecretName(cr), key.AppSecretNamespace(cr), "secret"); err != nil {
+		return microerror.Mask(err)
 	}
 
-	if key.AppSecretName(cr) != "" {
-		if err := v.validateNameAndNamespaceAreSet(key.AppSecretName(cr), key.AppSecretNamespace(cr), "secret"); err != nil {
-			return microerror.Mask(err)
-		}
-
-		if v.isAdmissionController {
-			v.logger.Debugf(ctx, "skipping '.spec.config.secret' validation of app '%s/%s' in admission controllers", cr.Namespace, cr.Name)
-		} else {
-			err := v.validateSecretExists(ctx, key.AppSecretName(cr), key.AppSecretNamespace(cr), "secret")
-			if apierrors.IsNotFound(err) {
-				return microerror.Maskf(validationError, resourceNotFoundTemplate, "secret", key.AppSecretName(cr), key.AppSecretNamespace(cr))
-			} else if err != nil {
-				return microerror.Mask(err)
-			}
-		}
+	if v.isAdmissionController {
+		v.logger.Debugf(ctx, "skipping '.spec.config.secret' validation of app '%s/%s' in admission controllers", cr.Namespace, cr.Name)
+		return nil
+	}
 
+	err := v.validateSecretExists(ctx, key.AppSecretName(cr), key.AppSecretNamespace(cr), "secret")
+	if apierrors.IsNotFound(err) {
+		return newFieldError(validationError, field.ErrorTypeNotFound, path.Child("name"), key.AppSecretName(cr), resourceNotFoundTemplate, "secret", key.AppSecretName(cr), key.AppSecretNamespace(cr))
+	} else if err != nil {
+		return microerror.Mask(err)
 	}
 
 	return nil
@@ -204,7 +264,7 @@ func (v *Validator) validateConfig(ctx context.Context, cr v1alpha1.App) error {
 
 func (v *Validator) validateName(ctx context.Context, cr v1alpha1.App) error {
 	if len(cr.Name) > nameMaxLength {
-		return microerror.Maskf(validationError, nameTooLongTemplate, cr.Name, len(cr.Name), nameMaxLength)
+		return newFieldError(validationError, field.ErrorTypeTooLong, metadataPath.Child("name"), cr.Name, nameTooLongTemplate, cr.Name, len(cr.Name), nameMaxLength)
 	}
 
 	return nil
@@ -218,7 +278,7 @@ func (v *Validator) validateTargetNamespace(ctx context.Context, cr v1alpha1.App
 	isOutsideOrg := cr.Namespace != cr.Spec.Namespace
 
 	if isInCluster && isNotGs && isOutsideOrg {
-		return microerror.Maskf(validationError, targetNamespaceNotAllowedTemplate, cr.Spec.Namespace)
+		return newFieldError(validationError, field.ErrorTypeForbidden, specPath.Child("namespace"), cr.Spec.Namespace, targetNamespaceNotAllowedTemplate, cr.Spec.Namespace)
 	}
 
 	return nil
@@ -263,7 +323,8 @@ func (v *Validator) validateNamespaceConfig(ctx context.Context, cr v1alpha1.App
 			for k, v := range targetAnnotations {
 				originalValue, ok := annotations[k]
 				if ok && originalValue != v {
-					return microerror.Maskf(validationError, "app %#q annotation %#q for target namespace %#q collides with value %#q for app %#q",
+					return newFieldError(validationError, field.ErrorTypeInvalid, specPath.Child("namespaceConfig", "annotations").Key(k), originalValue,
+						"app %#q annotation %#q for target namespace %#q collides with value %#q for app %#q",
 						key.AppName(cr), k, key.AppNamespace(cr), v, app.Name)
 				}
 			}
@@ -274,7 +335,8 @@ func (v *Validator) validateNamespaceConfig(ctx context.Context, cr v1alpha1.App
 			for k, v := range targetLabels {
 				originalValue, ok := labels[k]
 				if ok && originalValue != v {
-					return microerror.Maskf(validationError, "app %#q label %#q for target namespace %#q collides with value %#q for app %#q",
+					return newFieldError(validationError, field.ErrorTypeInvalid, specPath.Child("namespaceConfig", "labels").Key(k), originalValue,
+						"app %#q label %#q for target namespace %#q collides with value %#q for app %#q",
 						key.AppName(cr), k, key.AppNamespace(cr), v, app.Name)
 				}
 			}
@@ -286,7 +348,9 @@ func (v *Validator) validateNamespaceConfig(ctx context.Context, cr v1alpha1.App
 
 func (v *Validator) validateKubeConfig(ctx context.Context, cr v1alpha1.App) error {
 	if !key.InCluster(cr) {
-		if err := v.validateNameAndNamespaceAreSet(key.KubeConfigSecretName(cr), key.KubeConfigSecretNamespace(cr), "kubeconfig secret"); err != nil {
+		path := specPath.Child("kubeConfig", "secret")
+
+		if err := v.validateNameAndNamespaceAreSet(path, key.KubeConfigSecretName(cr), key.KubeConfigSecretNamespace(cr), "kubeconfig secret"); err != nil {
 			return microerror.Mask(err)
 		}
 
@@ -297,7 +361,7 @@ func (v *Validator) validateKubeConfig(ctx context.Context, cr v1alpha1.App) err
 			if apierrors.IsNotFound(err) {
 				// kubeConfigNotFoundError is used rather than a validation error because
 				// during cluster creation there is a short delay while it is generated.
-				return microerror.Maskf(kubeConfigNotFoundError, resourceNotFoundTemplate, "kubeconfig secret", key.KubeConfigSecretName(cr), key.KubeConfigSecretNamespace(cr))
+				return newFieldError(kubeConfigNotFoundError, field.ErrorTypeNotFound, path.Child("name"), key.KubeConfigSecretName(cr), resourceNotFoundTemplate, "kubeconfig secret", key.KubeConfigSecretName(cr), key.KubeConfigSecretNamespace(cr))
 			} else if err != nil {
 				return microerror.Mask(err)
 			}
@@ -322,13 +386,13 @@ func (v *Validator) validateLabels(ctx context.Context, cr v1alpha1.App) error {
 
 func (v *Validator) validateClusterLabels(ctx context.Context, cr v1alpha1.App) error {
 	if key.VersionLabel(cr) == "" {
-		return microerror.Maskf(validationError, labelNotFoundTemplate, label.AppOperatorVersion)
+		return newFieldError(validationError, field.ErrorTypeRequired, metadataPath.Child("labels").Key(label.AppOperatorVersion), "", labelNotFoundTemplate, label.AppOperatorVersion)
 	}
 	if key.VersionLabel(cr) == key.LegacyAppVersionLabel {
-		return microerror.Maskf(validationError, labelInvalidValueTemplate, label.AppOperatorVersion, key.VersionLabel(cr))
+		return newFieldError(validationError, field.ErrorTypeInvalid, metadataPath.Child("labels").Key(label.AppOperatorVersion), key.VersionLabel(cr), labelInvalidValueTemplate, label.AppOperatorVersion, key.VersionLabel(cr))
 	}
 	if key.InCluster(cr) && key.VersionLabel(cr) != key.UniqueAppVersionLabel {
-		return microerror.Maskf(validationError, labelInClusterAppTemplate, label.AppOperatorVersion)
+		return newFieldError(validationError, field.ErrorTypeInvalid, metadataPath.Child("labels").Key(label.AppOperatorVersion), key.VersionLabel(cr), labelInClusterAppTemplate, label.AppOperatorVersion)
 	}
 
 	return nil
@@ -336,10 +400,10 @@ func (v *Validator) validateClusterLabels(ctx context.Context, cr v1alpha1.App)
 
 func (v *Validator) validateOrgLabels(ctx context.Context, cr v1alpha1.App) error {
 	if key.ClusterLabel(cr) == "" {
-		return microerror.Maskf(validationError, labelNotFoundTemplate, label.Cluster)
+		return newFieldError(validationError, field.ErrorTypeRequired, metadataPath.Child("labels").Key(label.Cluster), "", labelNotFoundTemplate, label.Cluster)
 	}
 	if key.InCluster(cr) && key.VersionLabel(cr) != key.UniqueAppVersionLabel {
-		return microerror.Maskf(validationError, labelInClusterAppTemplate, label.AppOperatorVersion)
+		return newFieldError(validationError, field.ErrorTypeInvalid, metadataPath.Child("labels").Key(label.AppOperatorVersion), key.VersionLabel(cr), labelInClusterAppTemplate, label.AppOperatorVersion)
 	}
 
 	return nil
@@ -355,6 +419,7 @@ func (v *Validator) validateMetadataConstraints(ctx context.Context, cr v1alpha1
 	}, &entry)
 	if apierrors.IsNotFound(err) {
 		v.logger.Debugf(ctx, "appcatalogentry %#q not found, skipping metadata validation", name)
+		addWarning(ctx, "appcatalogentry %#q not found, metadata constraints of app %#q were not validated", name, cr.Spec.Name)
 		return nil
 	} else if err != nil {
 		return microerror.Mask(err)
@@ -367,14 +432,14 @@ func (v *Validator) validateMetadataConstraints(ctx context.Context, cr v1alpha1
 
 	if len(entry.Spec.Restrictions.CompatibleProviders) > 0 {
 		if !contains(entry.Spec.Restrictions.CompatibleProviders, v.provider) {
-			return microerror.Maskf(validationError, "app %#q can only be installed for providers %#q not %#q",
+			return newFieldError(validationError, field.ErrorTypeForbidden, specPath.Child("name"), cr.Spec.Name, "app %#q can only be installed for providers %#q not %#q",
 				cr.Spec.Name, entry.Spec.Restrictions.CompatibleProviders, v.provider)
 		}
 	}
 
 	if entry.Spec.Restrictions.FixedNamespace != "" {
 		if entry.Spec.Restrictions.FixedNamespace != cr.Spec.Namespace {
-			return microerror.Maskf(validationError, "app %#q can only be installed in namespace %#q only, not %#q",
+			return newFieldError(validationError, field.ErrorTypeForbidden, specPath.Child("namespace"), cr.Spec.Namespace, "app %#q can only be installed in namespace %#q only, not %#q",
 				cr.Spec.Name, entry.Spec.Restrictions.FixedNamespace, cr.Spec.Namespace)
 		}
 	}
@@ -420,7 +485,7 @@ func (v *Validator) validateMetadataConstraints(ctx context.Context, cr v1alpha1
 			if clusterId == "" {
 				clusterId = cr.Namespace
 			}
-			return microerror.Maskf(validationError, "app %#q can only be installed once in cluster %#q",
+			return newFieldError(validationError, field.ErrorTypeDuplicate, specPath.Child("name"), cr.Spec.Name, "app %#q can only be installed once in cluster %#q",
 				cr.Spec.Name, clusterId)
 		}
 
@@ -429,7 +494,7 @@ func (v *Validator) validateMetadataConstraints(ctx context.Context, cr v1alpha1
 		}
 
 		if app.Spec.Namespace == cr.Spec.Namespace {
-			return microerror.Maskf(validationError, "app %#q can only be installed only once in namespace %#q",
+			return newFieldError(validationError, field.ErrorTypeDuplicate, specPath.Child("name"), cr.Spec.Name, "app %#q can only be installed only once in namespace %#q",
 				cr.Spec.Name, key.Namespace(cr))
 		}
 	}
@@ -437,17 +502,30 @@ func (v *Validator) validateMetadataConstraints(ctx context.Context, cr v1alpha1
 	return nil
 }
 
+// validateVersion only warns about versions prefixed with `v`. They are
+// accepted so that Flux can set `v`-prefixed image tags as versions, see
+// key.Version, but the prefix is dropped silently otherwise.
+func (v *Validator) validateVersion(ctx context.Context, cr v1alpha1.App) error {
+	if strings.HasPrefix(cr.Spec.Version, "v") {
+		addWarning(ctx, "version %#q is prefixed with `v`, chart version %#q is used instead", cr.Spec.Version, key.Version(cr))
+	}
+
+	return nil
+}
+
 func (v *Validator) validateNamespaceUpdate(ctx context.Context, app, currentApp v1alpha1.App) error {
 	if key.Namespace(app) != key.Namespace(currentApp) {
-		return microerror.Maskf(validationError, "target namespace for app %#q cannot be changed from %#q to %#q", app.Name,
+		return newFieldError(validationError, field.ErrorTypeForbidden, specPath.Child("namespace"), key.Namespace(app), "target namespace for app %#q cannot be changed from %#q to %#q", app.Name,
 			key.Namespace(currentApp), key.Namespace(app))
 	}
 
 	return nil
 }
 
-func (v *Validator) validateUserConfig(ctx context.Context, cr v1alpha1.App) error {
+func (v *Validator) validateUserConfigMap(ctx context.Context, cr v1alpha1.App) error {
 	if key.UserConfigMapName(cr) != "" {
+		path := specPath.Child("userConfig", "configMap")
+
 		if key.CatalogName(cr) == defaultCatalogName {
 			// This check is for `cluster-operator` only. For CAPI clusters, that does not rely on
 			// `cluster-operator`, the names could be any, but since it hasn't been conditioned earlier,
@@ -479,20 +557,21 @@ func (v *Validator) validateUserConfig(ctx context.Context, cr v1alpha1.App) err
 			}
 
 			if nameMismatch {
-				return microerror.Maskf(validationError, "user configmap must be named %#q for app in default catalog", configMapName)
+				return newFieldError(validationError, field.ErrorTypeInvalid, path.Child("name"), key.UserConfigMapName(cr), "user configmap must be named %#q for app in default catalog", configMapName)
 			}
 		}
 
-		if err := v.validateNameAndNamespaceAreSet(key.UserConfigMapName(cr), key.UserConfigMapNamespace(cr), "configmap"); err != nil {
+		if err := v.validateNameAndNamespaceAreSet(path, key.UserConfigMapName(cr), key.UserConfigMapNamespace(cr), "configmap"); err != nil {
 			return microerror.Mask(err)
 		}
 
 		if v.isAdmissionController {
 			v.logger.Debugf(ctx, "skipping '.spec.userConfig.configMap' validation of app '%s/%s' in admission controllers", cr.Namespace, cr.Name)
+			addWarning(ctx, "existence of user configmap %#q in namespace %#q was not validated", key.UserConfigMapName(cr), key.UserConfigMapNamespace(cr))
 		} else {
 			err := v.validateConfigMapExists(ctx, key.UserConfigMapName(cr), key.UserConfigMapNamespace(cr), "configmap")
 			if apierrors.IsNotFound(err) {
-				return microerror.Maskf(validationError, resourceNotFoundTemplate, "configmap", key.UserConfigMapName(cr), key.UserConfigMapNamespace(cr))
+				return newFieldError(validationError, field.ErrorTypeNotFound, path.Child("name"), key.UserConfigMapName(cr), resourceNotFoundTemplate, "configmap", key.UserConfigMapName(cr), key.UserConfigMapNamespace(cr))
 			} else if err != nil {
 				return microerror.Mask(err)
 			}
@@ -500,7 +579,13 @@ func (v *Validator) validateUserConfig(ctx context.Context, cr v1alpha1.App) err
 
 	}
 
+	return nil
+}
+
+func (v *Validator) validateUserSecret(ctx context.Context, cr v1alpha1.App) error {
 	if key.UserSecretName(cr) != "" {
+		path := specPath.Child("userConfig", "secret")
+
 		if key.CatalogName(cr) == defaultCatalogName {
 			// This check is for `cluster-operator` only. For CAPI clusters, that does not rely on
 			// `cluster-operator`, the names could be any, but since it hasn't been conditioned earlier,
@@ -532,20 +617,21 @@ func (v *Validator) validateUserConfig(ctx context.Context, cr v1alpha1.App) err
 			}
 
 			if nameMismatch {
-				return microerror.Maskf(validationError, "user secret must be named %#q for app in default catalog", secretName)
+				return newFieldError(validationError, field.ErrorTypeInvalid, path.Child("name"), key.UserSecretName(cr), "user secret must be named %#q for app in default catalog", secretName)
 			}
 		}
 
-		if err := v.validateNameAndNamespaceAreSet(key.UserSecretName(cr), key.UserSecretNamespace(cr), "secret"); err != nil {
+		if err := v.validateNameAndNamespaceAreSet(path, key.UserSecretName(cr), key.UserSecretNamespace(cr), "secret"); err != nil {
 			return microerror.Mask(err)
 		}
 
 		if v.isAdmissionController {
 			v.logger.Debugf(ctx, "skipping '.spec.userConfig.secret' validation of app '%s/%s' in admission controllers", cr.Namespace, cr.Name)
+			addWarning(ctx, "existence of user secret %#q in namespace %#q was not validated", key.UserSecretName(cr), key.UserSecretNamespace(cr))
 		} else {
 			err := v.validateSecretExists(ctx, key.UserSecretName(cr), key.UserSecretNamespace(cr), "secret")
 			if apierrors.IsNotFound(err) {
-				return microerror.Maskf(validationError, resourceNotFoundTemplate, "secret", key.UserSecretName(cr), key.UserSecretNamespace(cr))
+				return newFieldError(validationError, field.ErrorTypeNotFound, path.Child("name"), key.UserSecretName(cr), resourceNotFoundTemplate, "secret", key.UserSecretName(cr), key.UserSecretNamespace(cr))
 			} else if err != nil {
 				return microerror.Mask(err)
 			}
@@ -556,13 +642,13 @@ func (v *Validator) validateUserConfig(ctx context.Context, cr v1alpha1.App) err
 	return nil
 }
 
-func (v *Validator) validateNameAndNamespaceAreSet(name, namespace, kind string) error {
+func (v *Validator) validateNameAndNamespaceAreSet(path *field.Path, name, namespace, kind string) error {
 	if namespace == "" {
-		return microerror.Maskf(validationError, namespaceNotFoundReasonTemplate, kind, name)
+		return newFieldError(validationError, field.ErrorTypeRequired, path.Child("namespace"), "", namespaceNotFoundReasonTemplate, kind, name)
 	}
 
 	if name == "" {
-		return microerror.Maskf(validationError, nameNotFoundReasonTemplate, kind)
+		return newFieldError(validationError, field.ErrorTypeRequired, path.Child("name"), "", nameNotFoundReasonTemplate, kind)
 	}
 
 	return nil
@@ -603,7 +689,7 @@ func (v *Validator) validateUniqueInClusterAppName(ctx context.Context, cr v1alp
 		FieldSelector: fields.OneTermEqualSelector(fieldName, fieldValue),
 	})
 	if err != nil {
-		return microerror.Maskf(validationError, "failed to list apps with %#q set to %#q to validate unique in-cluster app name rule, %#v", fieldName, fieldValue, err)
+		return newFieldError(validationError, field.ErrorTypeInternal, metadataPath.Child("name"), cr.Name, "failed to list apps with %#q set to %#q to validate unique in-cluster app name rule, %#v", fieldName, fieldValue, err)
 	}
 
 	for _, inspectedApp := range apps.Items {
@@ -621,15 +707,15 @@ func (v *Validator) validateUniqueInClusterAppName(ctx context.Context, cr v1alp
 		// See: https://github.com/kubernetes-sigs/controller-runtime/issues/866
 		if inspectedApp.Name == cr.Name {
 			if inspectedApp.Namespace == specialNamespace {
-				return microerror.Maskf(validationError, "found another app named %#q installed into the %#q namespace", inspectedApp.Name, specialNamespace)
+				return newFieldError(validationError, field.ErrorTypeDuplicate, metadataPath.Child("name"), cr.Name, "found another app named %#q installed into the %#q namespace", inspectedApp.Name, specialNamespace)
 			}
 
 			if key.InCluster(inspectedApp) {
 				if cr.Namespace == specialNamespace {
-					return microerror.Maskf(validationError, "there is in-cluster app named %#q already installed in the %#q namespace that would cause name collision with the currently submitted app named %#q in the %#q namespace", inspectedApp.Name, inspectedApp.Namespace, cr.Name, cr.Namespace)
+					return newFieldError(validationError, field.ErrorTypeDuplicate, metadataPath.Child("name"), cr.Name, "there is in-cluster app named %#q already installed in the %#q namespace that would cause name collision with the currently submitted app named %#q in the %#q namespace", inspectedApp.Name, inspectedApp.Namespace, cr.Name, cr.Namespace)
 				}
 
-				return microerror.Maskf(validationError, "in-cluster apps must be given a unique name, found an app named %#q as well in the %#q namespace", inspectedApp.Name, inspectedApp.Namespace)
+				return newFieldError(validationError, field.ErrorTypeDuplicate, metadataPath.Child("name"), cr.Name, "in-cluster apps must be given a unique name, found an app named %#q as well in the %#q namespace", inspectedApp.Name, inspectedApp.Namespace)
 			}
 		}
 	}
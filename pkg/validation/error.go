@@ -71,6 +71,15 @@ func IsKubeConfigNotFound(err error) bool {
 	return false
 }
 
+var ruleViolationError = &microerror.Error{
+	Kind: "ruleViolationError",
+}
+
+// IsRuleViolation asserts ruleViolationError.
+func IsRuleViolation(err error) bool {
+	return microerror.Cause(err) == ruleViolationError
+}
+
 var validationError = &microerror.Error{
 	Kind: "validationError",
 }
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var (
+	metadataPath = field.NewPath("metadata")
+	specPath     = field.NewPath("spec")
+)
+
+// fieldError is a validation failure of a single field of an app. It wraps
+// the error returned by ValidateApp, so that error matchers and messages
+// stay the same, and carries the failure as field error for ValidateAppAll.
+type fieldError struct {
+	err   error
+	field *field.Error
+}
+
+func (e *fieldError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.err
+}
+
+// newFieldError returns an error of the given kind annotated with the given
+// message that fails the field at the given path with the given error type
+// and bad value.
+func newFieldError(kind *microerror.Error, errType field.ErrorType, path *field.Path, value interface{}, f string, v ...interface{}) error {
+	return &fieldError{
+		err: microerror.Maskf(kind, f, v...),
+		field: &field.Error{
+			Type:     errType,
+			Field:    path.String(),
+			BadValue: value,
+			Detail:   fmt.Sprintf(f, v...),
+		},
+	}
+}
+
+// NewInvalidAppError returns the given failures of the given app as
+// Kubernetes API error. Its status can be used as the result of an admission
+// response.
+func NewInvalidAppError(app v1alpha1.App, errs field.ErrorList) *apierrors.StatusError {
+	return apierrors.NewInvalid(schema.GroupKind{Group: v1alpha1.SchemeGroupVersion.Group, Kind: "App"}, app.Name, errs)
+}
+
+// collectFieldErrors runs all the given validators and returns the failures
+// they found. Other errors stop the validation and are returned as is.
+func collectFieldErrors(ctx context.Context, app v1alpha1.App, validators []appValidator) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+
+	for _, validate := range validators {
+		err := validate(ctx, app)
+		if err == nil {
+			continue
+		}
+
+		var fieldErr *fieldError
+		if !errors.As(err, &fieldErr) {
+			return nil, microerror.Mask(err)
+		}
+
+		allErrs = append(allErrs, fieldErr.field)
+	}
+
+	return allErrs, nil
+}
@@ -0,0 +1,142 @@
+package validation
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake" //nolint:staticcheck
+)
+
+func Test_ValidateAppAll(t *testing.T) {
+	ctx := context.Background()
+
+	type failure struct {
+		Type  field.ErrorType
+		Field string
+	}
+
+	tests := []struct {
+		name             string
+		obj              v1alpha1.App
+		expectedFailures []failure
+	}{
+		{
+			name: "case 0: flawless",
+			obj: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "giantswarm",
+					Labels: map[string]string{
+						label.AppOperatorVersion: "0.0.0",
+					},
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:   "giantswarm",
+					Name:      "kiam",
+					Namespace: "kube-system",
+					KubeConfig: v1alpha1.AppSpecKubeConfig{
+						InCluster: true,
+					},
+					Version: "1.4.0",
+				},
+			},
+		},
+		{
+			name: "case 1: all failures are collected",
+			obj: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "eggs2",
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:   "missing",
+					Name:      "kiam",
+					Namespace: "kube-system",
+					Config: v1alpha1.AppSpecConfig{
+						ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+							Name: "eggs2-cluster-values",
+						},
+					},
+					KubeConfig: v1alpha1.AppSpecKubeConfig{
+						Secret: v1alpha1.AppSpecKubeConfigSecret{
+							Name: "eggs2-kubeconfig",
+						},
+					},
+					UserConfig: v1alpha1.AppSpecUserConfig{
+						Secret: v1alpha1.AppSpecUserConfigSecret{
+							Name:      "kiam-user-secrets",
+							Namespace: "eggs2",
+						},
+					},
+					Version: "1.4.0",
+				},
+			},
+			expectedFailures: []failure{
+				{Type: field.ErrorTypeNotFound, Field: "spec.catalog"},
+				{Type: field.ErrorTypeRequired, Field: "metadata.labels[app-operator.giantswarm.io/version]"},
+				{Type: field.ErrorTypeRequired, Field: "spec.config.configMap.namespace"},
+				{Type: field.ErrorTypeRequired, Field: "spec.kubeConfig.secret.namespace"},
+				{Type: field.ErrorTypeNotFound, Field: "spec.userConfig.secret.name"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = v1alpha1.AddToScheme(scheme)
+
+			fakeCtrlClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(newTestCatalog("giantswarm", "default")).
+				WithIndex(&v1alpha1.App{}, "metadata.name", appNameIndexer).
+				Build()
+
+			r, err := NewValidator(Config{
+				G8sClient: fakeCtrlClient,
+				K8sClient: clientgofake.NewClientset(),
+				Logger:    microloggertest.New(),
+
+				Provider: "aws",
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			allErrs, _, err := r.ValidateAppAll(ctx, tc.obj)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			var failures []failure
+			for _, e := range allErrs {
+				failures = append(failures, failure{Type: e.Type, Field: e.Field})
+			}
+			if diff := cmp.Diff(failures, tc.expectedFailures); diff != "" {
+				t.Fatalf("want matching failures \n %s", diff)
+			}
+
+			if len(allErrs) == 0 {
+				return
+			}
+
+			// The failures map onto the status of an admission response.
+			status := NewInvalidAppError(tc.obj, allErrs).Status()
+			if status.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("status code == %d, want %d", status.Code, http.StatusUnprocessableEntity)
+			}
+			if len(status.Details.Causes) != len(tc.expectedFailures) {
+				t.Fatalf("status causes == %d, want %d", len(status.Details.Causes), len(tc.expectedFailures))
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package validation
+
+import (
+	"context"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/app/v8/pkg/key"
+)
+
+// policyCostLimit is the maximum cost of evaluating a single policy, the
+// same as the per call limit of Kubernetes validating admission policies.
+// Evaluations exceeding it fail.
+const policyCostLimit = 1000000
+
+// Policy is a declarative validation rule written as CEL expression. The
+// expression must evaluate to true for valid apps. It can use the variables
+//
+//   - `object`, the App CR,
+//   - `catalog`, the Catalog of the app or null if it does not exist and
+//   - `appCatalogEntry`, the AppCatalogEntry of the app version or null if it
+//     does not exist,
+//
+// e.g. `object.spec.version.startsWith('1.')`. Policies set in
+// Config.Policies run as rules named after them.
+type Policy struct {
+	// Name identifies the policy like the name of a rule.
+	Name string `json:"name"`
+	// Expression is the CEL expression evaluating to true for valid apps.
+	Expression string `json:"expression"`
+	// Message is the message returned when the policy is violated. It
+	// defaults to the expression.
+	Message string `json:"message,omitempty"`
+	// Field is the path of the field reported as failing, e.g.
+	// `spec.catalog`. It defaults to `spec`.
+	Field string `json:"field,omitempty"`
+}
+
+// ParsePolicies parses the given YAML list of policies.
+func ParsePolicies(data []byte) ([]Policy, error) {
+	var policies []Policy
+	err := yaml.UnmarshalStrict(data, &policies)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "failed to parse policies, logs: %s", err.Error())
+	}
+
+	return policies, nil
+}
+
+// ReadPolicies reads the YAML list of policies in the given file.
+func ReadPolicies(path string) ([]Policy, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	policies, err := ParsePolicies(data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return policies, nil
+}
+
+// PoliciesFromConfigMap returns the policies of the given configmap. Every
+// key holds a YAML list of policies, they are read in the sorted order of
+// the keys.
+func PoliciesFromConfigMap(configMap *corev1.ConfigMap) ([]Policy, error) {
+	var policies []Policy
+
+	for _, k := range slices.Sorted(maps.Keys(configMap.Data)) {
+		p, err := ParsePolicies([]byte(configMap.Data[k]))
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "failed to parse key %#q of configmap %#q in namespace %#q, logs: %s", k, configMap.Name, configMap.Namespace, err.Error())
+		}
+
+		policies = append(policies, p...)
+	}
+
+	return policies, nil
+}
+
+// policyRules compiles the given policies into rules.
+func (v *Validator) policyRules(policies []Policy) ([]Rule, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("catalog", cel.DynType),
+		cel.Variable("appCatalogEntry", cel.DynType),
+	)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var rules []Rule
+
+	for _, p := range policies {
+		if p.Name == "" {
+			return nil, microerror.Maskf(invalidConfigError, "name of policy with expression %#q must not be empty", p.Expression)
+		}
+
+		ast, issues := env.Compile(p.Expression)
+		if issues.Err() != nil {
+			return nil, microerror.Maskf(invalidConfigError, "failed to compile policy %#q, logs: %s", p.Name, issues.Err().Error())
+		}
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return nil, microerror.Maskf(invalidConfigError, "policy %#q must evaluate to bool but evaluates to %s", p.Name, ast.OutputType())
+		}
+
+		program, err := env.Program(ast, cel.CostLimit(policyCostLimit), cel.InterruptCheckFrequency(100))
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "failed to compile policy %#q, logs: %s", p.Name, err.Error())
+		}
+
+		rules = append(rules, NewRule(p.Name, func(ctx context.Context, app v1alpha1.App) error {
+			return v.validatePolicy(ctx, p, program, app)
+		}))
+	}
+
+	return rules, nil
+}
+
+// validatePolicy evaluates the given compiled policy for the given app.
+// Policies failing to evaluate, e.g. because they access missing fields or
+// exceed the cost limit, are violated too.
+func (v *Validator) validatePolicy(ctx context.Context, p Policy, program cel.Program, cr v1alpha1.App) error {
+	vars, err := v.cachedPolicyVariables(ctx, cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	path := specPath
+	if p.Field != "" {
+		path = field.NewPath(p.Field)
+	}
+
+	message := p.Message
+	if message == "" {
+		message = p.Expression
+	}
+
+	out, _, err := program.ContextEval(ctx, vars)
+	if err != nil {
+		return NewRuleViolation(path, nil, "policy %#q failed to evaluate: %s", p.Name, err.Error())
+	}
+
+	valid, ok := out.Value().(bool)
+	if !ok {
+		return NewRuleViolation(path, nil, "policy %#q evaluated to %v instead of bool", p.Name, out.Value())
+	}
+	if !valid {
+		return NewRuleViolation(path, nil, "policy %#q violated: %s", p.Name, message)
+	}
+
+	return nil
+}
+
+type policyVariablesKey struct{}
+
+// policyVariablesCache holds the variables policies are evaluated with, so
+// that they are only built once when validating an app.
+type policyVariablesCache struct {
+	vars map[string]interface{}
+}
+
+// withPolicyVariables returns a context the variables of policies are cached
+// in while validating a single app.
+func withPolicyVariables(ctx context.Context) context.Context {
+	return context.WithValue(ctx, policyVariablesKey{}, &policyVariablesCache{})
+}
+
+// cachedPolicyVariables returns the variables policies are evaluated with,
+// building them only once per context created by withPolicyVariables.
+func (v *Validator) cachedPolicyVariables(ctx context.Context, cr v1alpha1.App) (map[string]interface{}, error) {
+	c, ok := ctx.Value(policyVariablesKey{}).(*policyVariablesCache)
+	if ok && c.vars != nil {
+		return c.vars, nil
+	}
+
+	vars, err := v.policyVariables(ctx, cr)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if ok {
+		c.vars = vars
+	}
+
+	return vars, nil
+}
+
+// policyVariables returns the variables policies are evaluated with.
+func (v *Validator) policyVariables(ctx context.Context, cr v1alpha1.App) (map[string]interface{}, error) {
+	object, err := toUnstructured(&cr)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	vars := map[string]interface{}{
+		"object":          object,
+		"catalog":         nil,
+		"appCatalogEntry": nil,
+	}
+
+	if key.CatalogName(cr) != "" {
+		catalog, err := v.findCatalog(ctx, cr)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if catalog != nil {
+			vars["catalog"], err = toUnstructured(catalog)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+		}
+	}
+
+	var entry v1alpha1.AppCatalogEntry
+	err = v.g8sClient.Get(ctx, client.ObjectKey{
+		Namespace: metav1.NamespaceDefault,
+		Name:      key.AppCatalogEntryName(key.CatalogName(cr), key.AppName(cr), key.Version(cr)),
+	}, &entry)
+	if apierrors.IsNotFound(err) {
+		// no-op
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	} else {
+		vars["appCatalogEntry"], err = toUnstructured(&entry)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	return vars, nil
+}
+
+func toUnstructured(obj interface{}) (map[string]interface{}, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return u, nil
+}
@@ -0,0 +1,311 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func Test_PoliciesFromConfigMap(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-policies",
+			Namespace: "giantswarm",
+		},
+		Data: map[string]string{
+			"versions.yaml": "- name: version-1\n  expression: object.spec.version.startsWith('1.')\n  message: only 1.x versions are allowed\n",
+			"catalogs.yaml": "- name: allowed-catalogs\n  expression: object.spec.catalog in ['giantswarm']\n  field: spec.catalog\n",
+		},
+	}
+
+	policies, err := PoliciesFromConfigMap(configMap)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	expectedPolicies := []Policy{
+		{Name: "allowed-catalogs", Expression: "object.spec.catalog in ['giantswarm']", Field: "spec.catalog"},
+		{Name: "version-1", Expression: "object.spec.version.startsWith('1.')", Message: "only 1.x versions are allowed"},
+	}
+	if diff := cmp.Diff(policies, expectedPolicies); diff != "" {
+		t.Fatalf("want matching policies \n %s", diff)
+	}
+
+	configMap.Data["invalid.yaml"] = "- name: invalid\n  unknown: true\n"
+
+	_, err = PoliciesFromConfigMap(configMap)
+	if !IsInvalidConfig(err) {
+		t.Fatalf("error == %#v, want invalid config", err)
+	}
+}
+
+func Test_Policies(t *testing.T) {
+	ctx := context.Background()
+
+	newApp := func(namespace, version string) v1alpha1.App {
+		return v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kiam",
+				Namespace: namespace,
+				Labels: map[string]string{
+					label.AppOperatorVersion: "0.0.0",
+				},
+			},
+			Spec: v1alpha1.AppSpec{
+				Catalog:   "giantswarm",
+				Name:      "kiam",
+				Namespace: namespace,
+				KubeConfig: v1alpha1.AppSpecKubeConfig{
+					InCluster: true,
+				},
+				Version: version,
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		policies        []Policy
+		obj             v1alpha1.App
+		expectedField   string
+		expectedMessage string
+		errorMatcher    func(error) bool
+	}{
+		{
+			name:     "case 0: policy passes",
+			policies: []Policy{{Name: "version-1", Expression: "object.spec.version.startsWith('1.')"}},
+			obj:      newApp("giantswarm", "1.4.0"),
+		},
+		{
+			name:            "case 1: policy violated",
+			policies:        []Policy{{Name: "version-1", Expression: "object.spec.version.startsWith('1.')", Message: "only 1.x versions are allowed"}},
+			obj:             newApp("giantswarm", "2.0.0"),
+			expectedField:   "spec",
+			expectedMessage: "policy `version-1` violated: only 1.x versions are allowed",
+			errorMatcher:    IsRuleViolation,
+		},
+		{
+			name: "case 2: allow-list of catalogs per namespace",
+			policies: []Policy{{
+				Name:       "allowed-catalogs",
+				Expression: "object.metadata.namespace != 'org-acme' || object.spec.catalog in ['acme']",
+				Field:      "spec.catalog",
+			}},
+			obj:             newApp("org-acme", "1.4.0"),
+			expectedField:   "spec.catalog",
+			expectedMessage: "policy `allowed-catalogs` violated: object.metadata.namespace != 'org-acme' || object.spec.catalog in ['acme']",
+			errorMatcher:    IsRuleViolation,
+		},
+		{
+			name:     "case 3: catalog variable",
+			policies: []Policy{{Name: "catalog", Expression: "catalog != null && catalog.metadata.namespace == 'default'"}},
+			obj:      newApp("giantswarm", "1.4.0"),
+		},
+		{
+			name:            "case 4: missing appcatalogentry is null",
+			policies:        []Policy{{Name: "entry", Expression: "appCatalogEntry != null"}},
+			obj:             newApp("giantswarm", "1.4.0"),
+			expectedField:   "spec",
+			expectedMessage: "policy `entry` violated: appCatalogEntry != null",
+			errorMatcher:    IsRuleViolation,
+		},
+		{
+			name:            "case 5: evaluation failure",
+			policies:        []Policy{{Name: "missing", Expression: "object.spec.missing == 'x'"}},
+			obj:             newApp("giantswarm", "1.4.0"),
+			expectedField:   "spec",
+			expectedMessage: "policy `missing` failed to evaluate: no such key: missing",
+			errorMatcher:    IsRuleViolation,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config := newTestRulesConfig(nil, nil)
+			config.Policies = tc.policies
+
+			r, err := NewValidator(config)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			_, err = r.ValidateApp(ctx, tc.obj)
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.errorMatcher == nil {
+				return
+			}
+
+			allErrs, _, err := r.ValidateAppAll(ctx, tc.obj)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+			if len(allErrs) != 1 {
+				t.Fatalf("failures == %v, want one", allErrs)
+			}
+			if allErrs[0].Field != tc.expectedField {
+				t.Fatalf("field == %#q, want %#q", allErrs[0].Field, tc.expectedField)
+			}
+			if !strings.Contains(allErrs[0].Detail, tc.expectedMessage) {
+				t.Fatalf("message == %#q, want it to contain %#q", allErrs[0].Detail, tc.expectedMessage)
+			}
+		})
+	}
+
+	t.Run("disabled policy", func(t *testing.T) {
+		config := newTestRulesConfig(nil, []string{"version-1"})
+		config.Policies = []Policy{{Name: "version-1", Expression: "object.spec.version.startsWith('1.')"}}
+
+		r, err := NewValidator(config)
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+
+		_, err = r.ValidateApp(ctx, newApp("giantswarm", "2.0.0"))
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+	})
+}
+
+func Test_InvalidPolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+	}{
+		{
+			name:   "case 0: missing name",
+			policy: Policy{Expression: "true"},
+		},
+		{
+			name:   "case 1: invalid expression",
+			policy: Policy{Name: "invalid", Expression: "object.spec.version.startsWith("},
+		},
+		{
+			name:   "case 2: expression not evaluating to bool",
+			policy: Policy{Name: "string", Expression: "'yes'"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config := newTestRulesConfig(nil, nil)
+			config.Policies = []Policy{tc.policy}
+
+			_, err := NewValidator(config)
+			if !IsInvalidConfig(err) {
+				t.Fatalf("error == %#v, want invalid config", err)
+			}
+		})
+	}
+}
+
+func Test_PolicyVariablesAreBuiltOnce(t *testing.T) {
+	ctx := context.Background()
+
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kiam",
+			Namespace: "giantswarm",
+			Labels: map[string]string{
+				label.AppOperatorVersion: "0.0.0",
+			},
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "giantswarm",
+			Name:      "kiam",
+			Namespace: "giantswarm",
+			KubeConfig: v1alpha1.AppSpecKubeConfig{
+				InCluster: true,
+			},
+			Version: "1.4.0",
+		},
+	}
+
+	gets := func(t *testing.T, policies []Policy) int {
+		t.Helper()
+
+		var count int
+
+		config := newTestRulesConfig(nil, nil)
+		config.G8sClient = interceptor.NewClient(config.G8sClient.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				count++
+				return c.Get(ctx, key, obj, opts...)
+			},
+		})
+		config.Policies = policies
+
+		r, err := NewValidator(config)
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+
+		_, _, err = r.ValidateAppAll(ctx, app)
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+
+		return count
+	}
+
+	withoutPolicies := gets(t, nil)
+	withPolicies := gets(t, []Policy{
+		{Name: "a", Expression: "catalog != null"},
+		{Name: "b", Expression: "appCatalogEntry == null"},
+		{Name: "c", Expression: "object.spec.version == '1.4.0'"},
+	})
+
+	// The catalog and the appcatalogentry are read once for all policies.
+	if withPolicies != withoutPolicies+2 {
+		t.Fatalf("gets == %d, want %d", withPolicies, withoutPolicies+2)
+	}
+}
+
+func Test_PolicyCostLimit(t *testing.T) {
+	ctx := context.Background()
+
+	list := "[" + strings.TrimSuffix(strings.Repeat("0,", 100), ",") + "]"
+
+	config := newTestRulesConfig(nil, nil)
+	config.Policies = []Policy{{
+		Name:       "expensive",
+		Expression: fmt.Sprintf("%[1]s.all(a, %[1]s.all(b, %[1]s.all(c, %[1]s.all(d, a == b))))", list),
+	}}
+
+	r, err := NewValidator(config)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kiam",
+			Namespace: "giantswarm",
+		},
+	}
+
+	err = r.validateRule(ctx, r.rules[0], app)
+	if !IsRuleViolation(err) {
+		t.Fatalf("error == %#v, want rule violation", err)
+	}
+	if !strings.Contains(err.Error(), "cost limit exceeded") {
+		t.Fatalf("error == %#q, want cost limit to be exceeded", err.Error())
+	}
+}
@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Rule is a custom validation rule, e.g. an organization specific policy.
+// Rules set in Config.Rules run after the built-in validations of
+// ValidateApp.
+type Rule interface {
+	// Name identifies the rule, e.g. in Config.DisabledRules. It must be
+	// unique.
+	Name() string
+	// Validate returns an error created with NewRuleViolation when the given
+	// app violates the rule. Any other error stops the validation, e.g.
+	// when an API call fails.
+	Validate(ctx context.Context, app v1alpha1.App) error
+}
+
+// NewRule returns a rule with the given name validating apps with the given
+// function.
+func NewRule(name string, validate func(ctx context.Context, app v1alpha1.App) error) Rule {
+	return &funcRule{
+		name:     name,
+		validate: validate,
+	}
+}
+
+// NewRuleViolation returns the error rules return when an app violates them.
+// It is matched by IsRuleViolation and fails the field at the given path with
+// the given bad value and message.
+func NewRuleViolation(path *field.Path, value interface{}, f string, v ...interface{}) error {
+	return newFieldError(ruleViolationError, field.ErrorTypeInvalid, path, value, f, v...)
+}
+
+type funcRule struct {
+	name     string
+	validate func(ctx context.Context, app v1alpha1.App) error
+}
+
+func (r *funcRule) Name() string {
+	return r.name
+}
+
+func (r *funcRule) Validate(ctx context.Context, app v1alpha1.App) error {
+	return r.validate(ctx, app)
+}
+
+// enabledRules returns the given rules in order without the disabled ones.
+// Rule names must be unique and disabled rules must exist.
+func enabledRules(rules []Rule, disabled []string) ([]Rule, error) {
+	names := map[string]bool{}
+	for _, r := range rules {
+		if names[r.Name()] {
+			return nil, microerror.Maskf(invalidConfigError, "rule %#q must be unique", r.Name())
+		}
+		names[r.Name()] = true
+	}
+
+	isDisabled := map[string]bool{}
+	for _, name := range disabled {
+		if !names[name] {
+			return nil, microerror.Maskf(invalidConfigError, "disabled rule %#q must exist", name)
+		}
+		isDisabled[name] = true
+	}
+
+	var enabled []Rule
+	for _, r := range rules {
+		if !isDisabled[r.Name()] {
+			enabled = append(enabled, r)
+		}
+	}
+
+	return enabled, nil
+}
+
+// validateRule runs the given rule. Violations are returned as they are,
+// other errors are masked.
+func (v *Validator) validateRule(ctx context.Context, rule Rule, cr v1alpha1.App) error {
+	err := rule.Validate(ctx, cr)
+	if IsRuleViolation(err) {
+		v.logger.Debugf(ctx, "app '%s/%s' violates rule %#q", cr.Namespace, cr.Name, rule.Name())
+		return err
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
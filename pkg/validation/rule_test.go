@@ -0,0 +1,174 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake" //nolint:staticcheck
+)
+
+func Test_Rules(t *testing.T) {
+	ctx := context.Background()
+
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kiam",
+			Namespace: "org-acme",
+			Labels: map[string]string{
+				label.AppOperatorVersion: "0.0.0",
+			},
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "giantswarm",
+			Name:      "kiam",
+			Namespace: "org-acme",
+			KubeConfig: v1alpha1.AppSpecKubeConfig{
+				InCluster: true,
+			},
+			Version: "1.4.0",
+		},
+	}
+
+	teamLabel := NewRule("team-label", func(ctx context.Context, app v1alpha1.App) error {
+		if app.Labels["application.giantswarm.io/team"] == "" {
+			return NewRuleViolation(field.NewPath("metadata", "labels").Key("application.giantswarm.io/team"), "", "team label is required")
+		}
+		return nil
+	})
+	allowedCatalogs := NewRule("allowed-catalogs", func(ctx context.Context, app v1alpha1.App) error {
+		if app.Namespace == "org-acme" && app.Spec.Catalog != "acme" {
+			return NewRuleViolation(field.NewPath("spec", "catalog"), app.Spec.Catalog, "catalog %#q is not allowed in namespace %#q", app.Spec.Catalog, app.Namespace)
+		}
+		return nil
+	})
+	failing := NewRule("failing", func(ctx context.Context, app v1alpha1.App) error {
+		return errors.New("failed to reach policy service")
+	})
+
+	tests := []struct {
+		name             string
+		rules            []Rule
+		disabledRules    []string
+		expectedFailures []string
+		errorMatcher     func(error) bool
+	}{
+		{
+			name: "case 0: no rules",
+		},
+		{
+			name:  "case 1: rules run in order",
+			rules: []Rule{teamLabel, allowedCatalogs},
+			expectedFailures: []string{
+				"metadata.labels[application.giantswarm.io/team]",
+				"spec.catalog",
+			},
+			errorMatcher: IsRuleViolation,
+		},
+		{
+			name:          "case 2: disabled rule",
+			rules:         []Rule{teamLabel, allowedCatalogs},
+			disabledRules: []string{"team-label"},
+			expectedFailures: []string{
+				"spec.catalog",
+			},
+			errorMatcher: IsRuleViolation,
+		},
+		{
+			name:          "case 3: all rules disabled",
+			rules:         []Rule{teamLabel, allowedCatalogs},
+			disabledRules: []string{"team-label", "allowed-catalogs"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestRulesValidator(t, tc.rules, tc.disabledRules)
+
+			_, err := r.ValidateApp(ctx, app)
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			allErrs, _, err := r.ValidateAppAll(ctx, app)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			var failures []string
+			for _, e := range allErrs {
+				failures = append(failures, e.Field)
+			}
+			if diff := cmp.Diff(failures, tc.expectedFailures); diff != "" {
+				t.Fatalf("want matching failures \n %s", diff)
+			}
+		})
+	}
+
+	t.Run("failing rule stops the validation", func(t *testing.T) {
+		r := newTestRulesValidator(t, []Rule{failing, teamLabel}, nil)
+
+		_, _, err := r.ValidateAppAll(ctx, app)
+		if err == nil || IsRuleViolation(err) {
+			t.Fatalf("error == %#v, want non-violation error", err)
+		}
+	})
+
+	t.Run("duplicate rule names", func(t *testing.T) {
+		_, err := NewValidator(newTestRulesConfig([]Rule{teamLabel, teamLabel}, nil))
+		if !IsInvalidConfig(err) {
+			t.Fatalf("error == %#v, want invalid config", err)
+		}
+	})
+
+	t.Run("unknown disabled rule", func(t *testing.T) {
+		_, err := NewValidator(newTestRulesConfig([]Rule{teamLabel}, []string{"missing"}))
+		if !IsInvalidConfig(err) {
+			t.Fatalf("error == %#v, want invalid config", err)
+		}
+	})
+}
+
+func newTestRulesConfig(rules []Rule, disabledRules []string) Config {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	fakeCtrlClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(newTestCatalog("giantswarm", "default")).
+		WithIndex(&v1alpha1.App{}, "metadata.name", appNameIndexer).
+		Build()
+
+	return Config{
+		G8sClient: fakeCtrlClient,
+		K8sClient: clientgofake.NewClientset(),
+		Logger:    microloggertest.New(),
+
+		Provider: "aws",
+
+		Rules:         rules,
+		DisabledRules: disabledRules,
+	}
+}
+
+func newTestRulesValidator(t *testing.T, rules []Rule, disabledRules []string) *Validator {
+	r, err := NewValidator(newTestRulesConfig(rules, disabledRules))
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	return r
+}
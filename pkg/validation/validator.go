@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"slices"
+
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	"k8s.io/client-go/kubernetes"
@@ -14,6 +16,14 @@ type Config struct {
 
 	IsAdmissionController bool
 	Provider              string
+
+	// Rules are custom validation rules run after the built-in validations
+	// in the order they are given.
+	Rules []Rule
+	// Policies are CEL policies run as rules after the custom rules.
+	Policies []Policy
+	// DisabledRules are the names of rules and policies that are not run.
+	DisabledRules []string
 }
 
 type Validator struct {
@@ -23,6 +33,7 @@ type Validator struct {
 
 	isAdmissionController bool
 	provider              string
+	rules                 []Rule
 }
 
 func NewValidator(config Config) (*Validator, error) {
@@ -48,5 +59,15 @@ func NewValidator(config Config) (*Validator, error) {
 		provider:              config.Provider,
 	}
 
+	policyRules, err := validator.policyRules(config.Policies)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	validator.rules, err = enabledRules(slices.Concat(config.Rules, policyRules), config.DisabledRules)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	return validator, nil
 }
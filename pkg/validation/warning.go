@@ -0,0 +1,32 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+)
+
+type warningsKey struct{}
+
+// warningRecorder collects the warnings found while validating an app.
+type warningRecorder struct {
+	warnings []string
+}
+
+// withWarnings returns a context validators can record warnings in and the
+// recorder collecting them.
+func withWarnings(ctx context.Context) (context.Context, *warningRecorder) {
+	r := &warningRecorder{}
+
+	return context.WithValue(ctx, warningsKey{}, r), r
+}
+
+// addWarning records an advisory finding that does not fail the validation.
+// It is dropped when the context holds no recorder.
+func addWarning(ctx context.Context, f string, v ...interface{}) {
+	r, ok := ctx.Value(warningsKey{}).(*warningRecorder)
+	if !ok {
+		return
+	}
+
+	r.warnings = append(r.warnings, fmt.Sprintf(f, v...))
+}
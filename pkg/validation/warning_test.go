@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake" //nolint:staticcheck
+)
+
+func Test_ValidateAppWithWarnings(t *testing.T) {
+	ctx := context.Background()
+
+	newApp := func(version string, userConfig v1alpha1.AppSpecUserConfig) v1alpha1.App {
+		return v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kiam",
+				Namespace: "giantswarm",
+				Labels: map[string]string{
+					label.AppOperatorVersion: "0.0.0",
+				},
+			},
+			Spec: v1alpha1.AppSpec{
+				Catalog:   "giantswarm",
+				Name:      "kiam",
+				Namespace: "kube-system",
+				KubeConfig: v1alpha1.AppSpecKubeConfig{
+					InCluster: true,
+				},
+				UserConfig: userConfig,
+				Version:    version,
+			},
+		}
+	}
+
+	tests := []struct {
+		name                  string
+		obj                   v1alpha1.App
+		catalogEntry          string
+		isAdmissionController bool
+		expectedWarnings      []string
+	}{
+		{
+			name:         "case 0: no warnings",
+			obj:          newApp("1.4.0", v1alpha1.AppSpecUserConfig{}),
+			catalogEntry: "giantswarm-kiam-1.4.0",
+		},
+		{
+			name: "case 1: missing appcatalogentry",
+			obj:  newApp("1.4.0", v1alpha1.AppSpecUserConfig{}),
+			expectedWarnings: []string{
+				"appcatalogentry `giantswarm-kiam-1.4.0` not found, metadata constraints of app `kiam` were not validated",
+			},
+		},
+		{
+			name:         "case 2: version prefixed with v",
+			obj:          newApp("v1.4.0", v1alpha1.AppSpecUserConfig{}),
+			catalogEntry: "giantswarm-kiam-1.4.0",
+			expectedWarnings: []string{
+				"version `v1.4.0` is prefixed with `v`, chart version `1.4.0` is used instead",
+			},
+		},
+		{
+			name: "case 3: user configmap skipped in admission controller",
+			obj: newApp("1.4.0", v1alpha1.AppSpecUserConfig{
+				ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+					Name:      "kiam-user-values",
+					Namespace: "giantswarm",
+				},
+			}),
+			catalogEntry:          "giantswarm-kiam-1.4.0",
+			isAdmissionController: true,
+			expectedWarnings: []string{
+				"existence of user configmap `kiam-user-values` in namespace `giantswarm` was not validated",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = v1alpha1.AddToScheme(scheme)
+
+			g8sObjs := []runtime.Object{
+				newTestCatalog("giantswarm", "default"),
+			}
+			if tc.catalogEntry != "" {
+				g8sObjs = append(g8sObjs, &v1alpha1.AppCatalogEntry{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      tc.catalogEntry,
+						Namespace: metav1.NamespaceDefault,
+					},
+				})
+			}
+
+			fakeCtrlClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(g8sObjs...).
+				WithIndex(&v1alpha1.App{}, "metadata.name", appNameIndexer).
+				Build()
+
+			r, err := NewValidator(Config{
+				G8sClient: fakeCtrlClient,
+				K8sClient: clientgofake.NewClientset(),
+				Logger:    microloggertest.New(),
+
+				IsAdmissionController: tc.isAdmissionController,
+				Provider:              "aws",
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			warnings, err := r.ValidateAppWithWarnings(ctx, tc.obj)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			if diff := cmp.Diff(warnings, tc.expectedWarnings); diff != "" {
+				t.Fatalf("want matching warnings \n %s", diff)
+			}
+		})
+	}
+}
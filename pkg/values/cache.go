@@ -0,0 +1,180 @@
+package values
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+// CacheConfig represents the configuration used to create a new cache.
+type CacheConfig struct {
+	// TTL is how long fetched data is served from the cache.
+	TTL time.Duration
+}
+
+// Cache keeps the data read by value sources for a limited time, so that
+// reconciling many apps referencing the same configmaps and secrets does
+// not read them from the API over and over again. It is safe for concurrent
+// use and can be shared by several values services via Config.Cache.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mutex     sync.Mutex
+	entries   map[cacheKey]cacheEntry
+	nextSweep time.Time
+	hits      uint64
+	misses    uint64
+}
+
+// CacheStats are the counters of a cache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Size is the number of cached objects which did not expire yet.
+	Size int
+}
+
+type cacheKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+type cacheEntry struct {
+	data    map[string]string
+	expires time.Time
+}
+
+// NewCache creates a new configured cache.
+func NewCache(config CacheConfig) (*Cache, error) {
+	if config.TTL <= 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.TTL must be greater than zero", config)
+	}
+
+	c := &Cache{
+		ttl: config.TTL,
+		now: time.Now,
+
+		entries: map[cacheKey]cacheEntry{},
+	}
+
+	return c, nil
+}
+
+// Invalidate drops the cached data of the object of the given kind, e.g.
+// KindConfigMap, namespace and name. Controllers call it when they see the
+// object change.
+func (c *Cache) Invalidate(kind, namespace, name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, cacheKey{kind: kind, namespace: namespace, name: name})
+}
+
+// InvalidateAll drops all cached data.
+func (c *Cache) InvalidateAll() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = map[cacheKey]cacheEntry{}
+}
+
+// Stats returns the current counters of the cache.
+func (c *Cache) Stats() CacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+
+	var size int
+	for _, entry := range c.entries {
+		if now.Before(entry.expires) {
+			size++
+		}
+	}
+
+	return CacheStats{
+		Hits:   c.hits,
+		Misses: c.misses,
+		Size:   size,
+	}
+}
+
+func (c *Cache) get(k cacheKey) (map[string]string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[k]
+	if ok && c.now().Before(entry.expires) {
+		c.hits++
+		return entry.data, true
+	}
+
+	if ok {
+		delete(c.entries, k)
+	}
+	c.misses++
+
+	return nil, false
+}
+
+func (c *Cache) set(k cacheKey, data map[string]string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+
+	// Objects which are not read again after they expired would otherwise
+	// stay in the cache forever. Sweeping at most once per TTL keeps the
+	// cost of set constant on average.
+	if !now.Before(c.nextSweep) {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl)
+	}
+
+	c.entries[k] = cacheEntry{
+		data:    data,
+		expires: now.Add(c.ttl),
+	}
+}
+
+// cachedSource is a ValueSource serving data of the wrapped source from a
+// cache. Errors, including objects not being found, are not cached.
+type cachedSource struct {
+	cache  *Cache
+	kind   string
+	source ValueSource
+}
+
+func (s *cachedSource) IsSecret() bool {
+	return s.source.IsSecret()
+}
+
+func (s *cachedSource) Get(ctx context.Context, name, namespace string) (map[string]string, error) {
+	if name == "" {
+		return s.source.Get(ctx, name, namespace)
+	}
+
+	k := cacheKey{kind: s.kind, namespace: namespace, name: name}
+
+	data, ok := s.cache.get(k)
+	if ok {
+		return data, nil
+	}
+
+	data, err := s.source.Get(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.set(k, data)
+
+	return data, nil
+}
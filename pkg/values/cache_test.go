@@ -0,0 +1,148 @@
+package values
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_Cache(t *testing.T) {
+	ctx := context.Background()
+
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+				},
+			},
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+					Name:      "missing-user-values",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	k8sClient := clientgofake.NewClientset(
+		getTestCatalogConfigMapDefinition(map[string]string{
+			"values": "catalog: test\n",
+		}),
+		getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{
+			"values": "cluster: test\n",
+		}),
+	)
+
+	cache, err := NewCache(CacheConfig{TTL: time.Minute})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	v, err := New(Config{
+		K8sClient: k8sClient,
+		Logger:    microloggertest.New(),
+		Cache:     cache,
+	})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	catalog := getSimpleTestCatalogDefinitionWithConfigMap()
+
+	assertStats := func(t *testing.T, expected CacheStats, expectedGets int) {
+		t.Helper()
+
+		stats := cache.Stats()
+		if stats != expected {
+			t.Fatalf("stats == %#v, want %#v", stats, expected)
+		}
+
+		gets := 0
+		for _, a := range k8sClient.Actions() {
+			if a.GetVerb() == "get" {
+				gets++
+			}
+		}
+		if gets != expectedGets {
+			t.Fatalf("%d gets, want %d", gets, expectedGets)
+		}
+	}
+
+	// Errors are not cached so the missing user configmap is read every
+	// time.
+	_, err = v.MergeConfigMapData(ctx, app, catalog)
+	if !IsNotFound(err) {
+		t.Fatalf("error == %#v, want not found", err)
+	}
+	assertStats(t, CacheStats{Hits: 0, Misses: 3, Size: 2}, 3)
+
+	_, err = v.MergeConfigMapData(ctx, app, catalog)
+	if !IsNotFound(err) {
+		t.Fatalf("error == %#v, want not found", err)
+	}
+	assertStats(t, CacheStats{Hits: 2, Misses: 4, Size: 2}, 4)
+
+	cache.Invalidate(KindConfigMap, "giantswarm", "test-catalog-values")
+	app.Spec.UserConfig = v1alpha1.AppSpecUserConfig{}
+
+	_, err = v.MergeConfigMapData(ctx, app, catalog)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	assertStats(t, CacheStats{Hits: 3, Misses: 5, Size: 2}, 5)
+
+	// Expired entries are read again.
+	now = now.Add(time.Minute)
+
+	_, err = v.MergeConfigMapData(ctx, app, catalog)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	assertStats(t, CacheStats{Hits: 3, Misses: 7, Size: 2}, 7)
+
+	cache.InvalidateAll()
+	assertStats(t, CacheStats{Hits: 3, Misses: 7, Size: 0}, 7)
+}
+
+func Test_CacheSweepsExpiredEntries(t *testing.T) {
+	cache, err := NewCache(CacheConfig{TTL: time.Minute})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.set(cacheKey{kind: KindConfigMap, namespace: "giantswarm", name: "a"}, map[string]string{})
+	cache.set(cacheKey{kind: KindConfigMap, namespace: "giantswarm", name: "b"}, map[string]string{})
+
+	// Expired entries are not counted even before they are evicted.
+	now = now.Add(time.Minute)
+	if size := cache.Stats().Size; size != 0 {
+		t.Fatalf("size == %d, want 0", size)
+	}
+
+	// Entries never read again are evicted by later writes.
+	cache.set(cacheKey{kind: KindConfigMap, namespace: "giantswarm", name: "c"}, map[string]string{})
+	if len(cache.entries) != 1 {
+		t.Fatalf("%d entries, want 1", len(cache.entries))
+	}
+	if size := cache.Stats().Size; size != 1 {
+		t.Fatalf("size == %d, want 1", size)
+	}
+}
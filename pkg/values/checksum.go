@@ -0,0 +1,109 @@
+package values
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+// Checksums holds the checksums of resolved values. Controllers can store
+// them, e.g. as annotations, and skip Helm upgrades when they did not change.
+type Checksums struct {
+	// Values is the checksum of all merged values or, when
+	// Config.SeparateSecretChecksum is set, of the merged values not read
+	// from secrets only. It is keyed with Config.SecretChecksumKey when it
+	// covers secret values.
+	Values string `json:"values"`
+	// Secrets is the checksum of the merged values read from secrets,
+	// including values read through secret references, keyed with
+	// Config.SecretChecksumKey. It is only set when
+	// Config.SeparateSecretChecksum is set.
+	Secrets string `json:"secrets,omitempty"`
+}
+
+// MergeAllWithChecksums works like MergeAll but also returns the checksums
+// of the values.
+func (v *Values) MergeAllWithChecksums(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (map[string]interface{}, *Checksums, error) {
+	checksums := &Checksums{}
+
+	data, err := v.mergeAll(ctx, app, catalog, nil, checksums, nil)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	return data, checksums, nil
+}
+
+// Checksum returns the hex encoded SHA-256 checksum of the given values. It
+// only depends on the values and not on the order maps are iterated in. No
+// values and empty values have the same checksum.
+func Checksum(values map[string]interface{}) (string, error) {
+	sum, err := checksum(values, nil)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return sum, nil
+}
+
+// checksum works like Checksum but returns the hex encoded HMAC-SHA-256 of
+// the given values when key is not empty.
+func checksum(values map[string]interface{}, key []byte) (string, error) {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	// JSON encoding sorts map keys, which makes it deterministic.
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if len(key) == 0 {
+		sum := sha256.Sum256(raw)
+		return hex.EncodeToString(sum[:]), nil
+	}
+
+	mac := hmac.New(sha256.New, key)
+	_, err = mac.Write(raw)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// setChecksums sets the checksums of the given merged values. When
+// Config.SeparateSecretChecksum is set the values are split by the given
+// provenance, so that both checksums cover the values after references were
+// resolved and deleted values were removed.
+func (v *Values) setChecksums(checksums *Checksums, data map[string]interface{}, provenance *Provenance) error {
+	var err error
+
+	if !v.separateSecretChecksum {
+		checksums.Values, err = checksum(data, v.secretChecksumKey)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		return nil
+	}
+
+	split := v.splitSecretValues(data, provenance)
+
+	checksums.Values, err = Checksum(split.values)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	checksums.Secrets, err = checksum(split.secretValues, v.secretChecksumKey)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
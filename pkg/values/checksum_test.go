@@ -0,0 +1,336 @@
+package values
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_Checksum(t *testing.T) {
+	a := map[string]interface{}{
+		"replicas": float64(2),
+		"ingress": map[string]interface{}{
+			"enabled": true,
+			"hosts":   []interface{}{"a", "b"},
+		},
+	}
+
+	// Build the same values in a different insertion order.
+	b := map[string]interface{}{}
+	b["ingress"] = map[string]interface{}{
+		"hosts":   []interface{}{"a", "b"},
+		"enabled": true,
+	}
+	b["replicas"] = float64(2)
+
+	checksumA, err := Checksum(a)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	for i := 0; i < 10; i++ {
+		checksumB, err := Checksum(b)
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+		if checksumA != checksumB {
+			t.Fatalf("checksum == %#q, want %#q", checksumB, checksumA)
+		}
+	}
+
+	b["replicas"] = float64(3)
+	checksumB, err := Checksum(b)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	if checksumA == checksumB {
+		t.Fatalf("checksum == %#q, want a different one", checksumB)
+	}
+}
+
+func Test_MergeAllWithChecksums(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+				},
+				Secret: v1alpha1.AppSpecConfigSecret{
+					Name:      "test-cluster-secrets",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	objects := func(secret string) *clientgofake.Clientset {
+		return clientgofake.NewClientset(
+			getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{
+				"values": "cluster: test\n",
+			}),
+			getSecretDefinition("test-cluster-secrets", "giantswarm", map[string][]byte{
+				"values": []byte("secret: " + secret + "\n"),
+			}),
+		)
+	}
+
+	checksums := func(t *testing.T, separate bool, secret string) *Checksums {
+		t.Helper()
+
+		v, err := New(Config{
+			K8sClient:              objects(secret),
+			Logger:                 microloggertest.New(),
+			SeparateSecretChecksum: separate,
+		})
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+
+		data, checksums, err := v.MergeAllWithChecksums(context.Background(), app, v1alpha1.Catalog{})
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+
+		if separate {
+			return checksums
+		}
+
+		expected, err := Checksum(data)
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+		if checksums.Values != expected || checksums.Secrets != "" {
+			t.Fatalf("checksums == %#v, want values checksum %#q only", checksums, expected)
+		}
+
+		return checksums
+	}
+
+	if checksums(t, false, "a").Values == checksums(t, false, "b").Values {
+		t.Fatalf("want checksum to change with the secret")
+	}
+
+	separateA := checksums(t, true, "a")
+	separateB := checksums(t, true, "b")
+	if separateA.Values != separateB.Values {
+		t.Fatalf("want values checksum to not depend on the secret")
+	}
+	if separateA.Secrets == separateB.Secrets || separateA.Secrets == "" {
+		t.Fatalf("want secrets checksum to change with the secret")
+	}
+}
+
+func Test_MergeAllWithKeyedChecksums(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+				},
+				Secret: v1alpha1.AppSpecConfigSecret{
+					Name:      "test-cluster-secrets",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	checksums := func(t *testing.T, separate bool, key []byte) *Checksums {
+		t.Helper()
+
+		v, err := New(Config{
+			K8sClient: clientgofake.NewClientset(
+				getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{
+					"values": "cluster: test\n",
+				}),
+				getSecretDefinition("test-cluster-secrets", "giantswarm", map[string][]byte{
+					"values": []byte("password: hunter2\n"),
+				}),
+			),
+			Logger:                 microloggertest.New(),
+			SecretChecksumKey:      key,
+			SeparateSecretChecksum: separate,
+		})
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+
+		_, checksums, err := v.MergeAllWithChecksums(context.Background(), app, v1alpha1.Catalog{})
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+
+		return checksums
+	}
+
+	// Without a key the secrets checksum can be found by hashing guesses.
+	guessed, err := Checksum(map[string]interface{}{"password": "hunter2"})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	unkeyed := checksums(t, true, nil)
+	if unkeyed.Secrets != guessed {
+		t.Fatalf("secrets checksum == %#q, want %#q", unkeyed.Secrets, guessed)
+	}
+
+	keyedA := checksums(t, true, []byte("a"))
+	keyedB := checksums(t, true, []byte("b"))
+	if keyedA.Secrets == guessed || keyedA.Secrets == keyedB.Secrets {
+		t.Fatalf("want secrets checksum to depend on the key")
+	}
+	if keyedA.Secrets != checksums(t, true, []byte("a")).Secrets {
+		t.Fatalf("want secrets checksum to be stable for the same key")
+	}
+	if keyedA.Values != unkeyed.Values {
+		t.Fatalf("want values checksum to not depend on the key")
+	}
+
+	// Without separate checksums the values checksum covers the secrets and
+	// is keyed too.
+	if checksums(t, false, []byte("a")).Values == checksums(t, false, nil).Values {
+		t.Fatalf("want values checksum covering secrets to depend on the key")
+	}
+}
+
+func Test_MergeAllWithSeparateChecksums(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+					Name:      "test-user-values",
+					Namespace: "giantswarm",
+				},
+				Secret: v1alpha1.AppSpecUserConfigSecret{
+					Name:      "test-user-secrets",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                   string
+		values                 string
+		oldSecrets             map[string][]byte
+		newSecrets             map[string][]byte
+		expectedValuesChanged  bool
+		expectedSecretsChanged bool
+	}{
+		{
+			name:   "case 0: changed referenced secret changes the secrets checksum",
+			values: "password:\n  valueFrom:\n    secretKeyRef:\n      name: shared-credentials\n      key: password\n",
+			oldSecrets: map[string][]byte{
+				"test-user-secrets":  []byte("s: secret\n"),
+				"shared-credentials": []byte("a"),
+			},
+			newSecrets: map[string][]byte{
+				"test-user-secrets":  []byte("s: secret\n"),
+				"shared-credentials": []byte("b"),
+			},
+			expectedSecretsChanged: true,
+		},
+		{
+			name:   "case 1: value deleted by secret changes the values checksum",
+			values: "foo: bar\nbaz: qux\n",
+			oldSecrets: map[string][]byte{
+				"test-user-secrets": []byte("s: secret\n"),
+			},
+			newSecrets: map[string][]byte{
+				"test-user-secrets": []byte("s: secret\nfoo: null\n"),
+			},
+			expectedValuesChanged: true,
+		},
+		{
+			name:   "case 2: unchanged values keep both checksums",
+			values: "foo: bar\n",
+			oldSecrets: map[string][]byte{
+				"test-user-secrets": []byte("s: secret\n"),
+			},
+			newSecrets: map[string][]byte{
+				"test-user-secrets": []byte("s: secret\n"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			checksums := func(secrets map[string][]byte) (map[string]interface{}, *Checksums) {
+				k8sClient := clientgofake.NewClientset(
+					getConfigMapDefinition("test-user-values", "giantswarm", map[string]string{
+						"values": tc.values,
+					}),
+				)
+				for name, data := range secrets {
+					key := "values"
+					if name == "shared-credentials" {
+						key = "password"
+					}
+
+					_, err := k8sClient.CoreV1().Secrets("giantswarm").Create(context.Background(), getSecretDefinition(name, "giantswarm", map[string][]byte{key: data}), metav1.CreateOptions{})
+					if err != nil {
+						t.Fatalf("error == %#v, want nil", err)
+					}
+				}
+
+				v, err := New(Config{
+					K8sClient: k8sClient,
+					Logger:    microloggertest.New(),
+
+					MergeStrategy:          MergeStrategy{DeleteNulls: true},
+					ResolveReferences:      true,
+					SeparateSecretChecksum: true,
+				})
+				if err != nil {
+					t.Fatalf("error == %#v, want nil", err)
+				}
+
+				data, checksums, err := v.MergeAllWithChecksums(context.Background(), app, v1alpha1.Catalog{})
+				if err != nil {
+					t.Fatalf("error == %#v, want nil", err)
+				}
+
+				return data, checksums
+			}
+
+			oldData, oldChecksums := checksums(tc.oldSecrets)
+			newData, newChecksums := checksums(tc.newSecrets)
+
+			if (len(DiffValues(oldData, newData)) > 0) != (tc.expectedValuesChanged || tc.expectedSecretsChanged) {
+				t.Fatalf("want values to change only when a checksum is expected to change")
+			}
+			if (oldChecksums.Values != newChecksums.Values) != tc.expectedValuesChanged {
+				t.Fatalf("values checksum changed == %t, want %t", oldChecksums.Values != newChecksums.Values, tc.expectedValuesChanged)
+			}
+			if (oldChecksums.Secrets != newChecksums.Secrets) != tc.expectedSecretsChanged {
+				t.Fatalf("secrets checksum changed == %t, want %t", oldChecksums.Secrets != newChecksums.Secrets, tc.expectedSecretsChanged)
+			}
+		})
+	}
+}
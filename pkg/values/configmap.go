@@ -6,9 +6,10 @@ import (
 
 	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
 	"github.com/giantswarm/microerror"
-	"github.com/imdario/mergo"
+	"github.com/giantswarm/micrologger"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/giantswarm/app/v8/pkg/key"
 )
@@ -21,132 +22,156 @@ const (
 // MergeConfigMapData merges the data from the catalog, app, user and extra config configmaps
 // and returns a single set of values.
 func (v *Values) MergeConfigMapData(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (map[string]interface{}, error) {
-	appConfigMapName := key.AppConfigMapName(app)
-	catalogConfigMapName := key.CatalogConfigMapName(catalog)
-	userConfigMapName := key.UserConfigMapName(app)
+	data, err := v.mergeConfigMapData(ctx, app, catalog, nil, nil)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
 
-	extraConfigs := key.ConfigMapExtraConfigs(app)
+	return data, nil
+}
 
-	if appConfigMapName == "" && catalogConfigMapName == "" && userConfigMapName == "" && len(extraConfigs) == 0 {
-		// Return early as there is no config.
-		return nil, nil
+// MergeConfigMapDataWithProvenance works like MergeConfigMapData but also
+// returns the provenance of every merged value.
+func (v *Values) MergeConfigMapDataWithProvenance(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (map[string]interface{}, *Provenance, error) {
+	provenance := NewProvenance()
+
+	data, err := v.mergeConfigMapData(ctx, app, catalog, provenance, nil)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
 	}
 
-	// We get the catalog level values if configured.
-	rawCatalogData, err := v.getConfigMapForCatalog(ctx, catalog)
+	return data, provenance, nil
+}
+
+// mergeConfigMapData merges the configmap values. When provenance is not nil
+// the origin of every merged value is recorded in it and when warnings is not
+// nil the warnings found are appended to it.
+func (v *Values) mergeConfigMapData(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog, provenance *Provenance, warnings *[]Warning) (map[string]interface{}, error) {
+	origins, err := v.configMapOrigins(app, catalog)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	catalogData, err := extractData(configmap, "catalog", rawCatalogData)
+	layers, err := v.fetchLayers(ctx, app, origins)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	if catalogData == nil {
-		// If there is no catalog data then treat it as an empty map otherwise `mergo.Merge` will silently
-		// fail to merge the first layers: `dst = nil; mergo.Merge(dst, MAP_OF_DATA)` and `dst` is still nil
-		catalogData = map[string]interface{}{}
+	leaks := v.scanForSecretLeaks(ctx, layers)
+	if warnings != nil {
+		*warnings = append(*warnings, leaks...)
+		*warnings = append(*warnings, skippedLayers(layers)...)
 	}
 
-	// Pre cluster extra config maps
-	err = v.fetchAndMergeExtraConfigs(ctx, getPreClusterExtraConfigMapEntries(extraConfigs), v.getConfigMap, catalogData)
+	data, err := v.mergeLayers(ctx, layers, provenance)
 	if err != nil {
-		return nil, err
+		return nil, microerror.Mask(err)
 	}
 
-	// We get the app level values if configured.
-	rawAppData, err := v.getConfigMapForApp(ctx, app)
-	if err != nil {
-		return nil, microerror.Mask(err)
+	pruneDeleted(data)
+	if provenance != nil {
+		provenance.pruneDeleted()
 	}
 
-	appData, err := extractData(configmap, "app", rawAppData)
+	err = v.interpolate(app, data)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	err = mergo.Merge(&catalogData, appData, mergo.WithOverride)
+	// Secret references fail here, as configmap values are not kept secret.
+	err = v.resolveReferences(ctx, app, data, provenance, false)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	// Post cluster / pre user extra config maps
-	err = v.fetchAndMergeExtraConfigs(ctx, getPostClusterPreUserExtraConfigMapEntries(extraConfigs), v.getConfigMap, catalogData)
+	err = v.checkTotalSize(app, data)
 	if err != nil {
-		return nil, err
+		return nil, microerror.Mask(err)
 	}
 
-	// We get the user level values if configured and merge them.
-	if key.UserConfigMapName(app) != "" {
-		rawUserData, err := v.getUserConfigMapForApp(ctx, app)
-		if err != nil {
-			return nil, microerror.Mask(err)
-		}
+	return data, nil
+}
 
-		userData, err := extractData(configmap, "user", rawUserData)
-		if err != nil {
-			return nil, microerror.Mask(err)
-		}
+// configMapOrigins returns the configmaps feeding the values of the given app
+// in the order they are merged: catalog, pre cluster extra configs, app, post
+// cluster / pre user extra configs, user and post user extra configs.
+func (v *Values) configMapOrigins(app v1alpha1.App, catalog v1alpha1.Catalog) ([]Origin, error) {
+	extraConfigs, err := v.extraConfigs(app, false)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
 
-		err = mergo.Merge(&catalogData, userData, mergo.WithOverride)
-		if err != nil {
-			return nil, microerror.Mask(err)
-		}
+	if key.AppConfigMapName(app) == "" && key.CatalogConfigMapName(catalog) == "" && key.UserConfigMapName(app) == "" && len(extraConfigs) == 0 {
+		return nil, nil
 	}
 
-	// Post user extra config maps
-	err = v.fetchAndMergeExtraConfigs(ctx, getPostUserExtraConfigMapEntries(extraConfigs), v.getConfigMap, catalogData)
-	if err != nil {
-		return nil, err
+	var origins []Origin
+
+	if key.CatalogConfigMapName(catalog) != "" {
+		origins = append(origins, Origin{
+			Kind:      KindConfigMap,
+			Name:      key.CatalogConfigMapName(catalog),
+			Namespace: key.CatalogConfigMapNamespace(catalog),
+			Priority:  v1alpha1.ConfigPriorityCatalog,
+			Layer:     LayerCatalog,
+		})
 	}
 
-	return catalogData, nil
-}
+	origins = append(origins, extraConfigOrigins(getPreClusterExtraConfigs(extraConfigs, v.isConfigMapKind))...)
 
-func (v *Values) getConfigMap(ctx context.Context, configMapName, configMapNamespace string) (map[string]string, error) {
-	if configMapName == "" {
-		// Return early as no configmap has been specified.
-		return nil, nil
+	if key.AppConfigMapName(app) != "" {
+		origins = append(origins, Origin{
+			Kind:      KindConfigMap,
+			Name:      key.AppConfigMapName(app),
+			Namespace: key.AppConfigMapNamespace(app),
+			Priority:  v1alpha1.ConfigPriorityCluster,
+			Layer:     LayerApp,
+		})
 	}
 
-	v.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("looking for configmap %#q in namespace %#q", configMapName, configMapNamespace))
+	origins = append(origins, extraConfigOrigins(getPostClusterPreUserExtraConfigs(extraConfigs, v.isConfigMapKind))...)
 
-	configMap, err := v.k8sClient.CoreV1().ConfigMaps(configMapNamespace).Get(ctx, configMapName, metav1.GetOptions{})
-	if apierrors.IsNotFound(err) {
-		return nil, microerror.Maskf(notFoundError, "configmap %#q in namespace %#q not found", configMapName, configMapNamespace)
-	} else if err != nil {
-		return nil, microerror.Mask(err)
+	if key.UserConfigMapName(app) != "" {
+		origins = append(origins, Origin{
+			Kind:      KindConfigMap,
+			Name:      key.UserConfigMapName(app),
+			Namespace: key.UserConfigMapNamespace(app),
+			Priority:  v1alpha1.ConfigPriorityUser,
+			Layer:     LayerUser,
+		})
 	}
 
-	v.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found configmap %#q in namespace %#q", configMapName, configMapNamespace))
+	origins = append(origins, extraConfigOrigins(getPostUserExtraConfigs(extraConfigs, v.isConfigMapKind))...)
 
-	return configMap.Data, nil
+	return origins, nil
 }
 
-func (v *Values) getConfigMapForApp(ctx context.Context, app v1alpha1.App) (map[string]string, error) {
-	configMap, err := v.getConfigMap(ctx, key.AppConfigMapName(app), key.AppConfigMapNamespace(app))
-	if err != nil {
-		return nil, microerror.Mask(err)
-	}
+// configMapSource is the ValueSource reading values from configmaps.
+type configMapSource struct {
+	k8sClient kubernetes.Interface
+	logger    micrologger.Logger
+}
 
-	return configMap, nil
+func (s *configMapSource) IsSecret() bool {
+	return false
 }
 
-func (v *Values) getConfigMapForCatalog(ctx context.Context, catalog v1alpha1.Catalog) (map[string]string, error) {
-	configMap, err := v.getConfigMap(ctx, key.CatalogConfigMapName(catalog), key.CatalogConfigMapNamespace(catalog))
-	if err != nil {
-		return nil, microerror.Mask(err)
+func (s *configMapSource) Get(ctx context.Context, configMapName, configMapNamespace string) (map[string]string, error) {
+	if configMapName == "" {
+		// Return early as no configmap has been specified.
+		return nil, nil
 	}
 
-	return configMap, nil
-}
+	s.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("looking for configmap %#q in namespace %#q", configMapName, configMapNamespace))
 
-func (v *Values) getUserConfigMapForApp(ctx context.Context, app v1alpha1.App) (map[string]string, error) {
-	configMap, err := v.getConfigMap(ctx, key.UserConfigMapName(app), key.UserConfigMapNamespace(app))
-	if err != nil {
+	configMap, err := s.k8sClient.CoreV1().ConfigMaps(configMapNamespace).Get(ctx, configMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, microerror.Maskf(notFoundError, "configmap %#q in namespace %#q not found", configMapName, configMapNamespace)
+	} else if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	return configMap, nil
+	s.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found configmap %#q in namespace %#q", configMapName, configMapNamespace))
+
+	return configMap.Data, nil
 }
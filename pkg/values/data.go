@@ -0,0 +1,94 @@
+package values
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/giantswarm/microerror"
+	"github.com/imdario/mergo"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DataKeyModeSingle requires configmaps and secrets to hold their values
+	// as YAML under exactly one key, whatever its name.
+	DataKeyModeSingle = "single"
+	// DataKeyModeMerge merges the YAML values under all keys of configmaps
+	// and secrets in the sorted order of the keys, so later keys override
+	// earlier ones.
+	DataKeyModeMerge = "merge"
+	// DataKeyModeNested puts the YAML value under every key of configmaps
+	// and secrets at the top-level value path named after the key.
+	DataKeyModeNested = "nested"
+)
+
+// extractValues parses the values in the data read for the given origin
+// according to the configured data key and data key mode. Data without any
+// keys holds no values.
+func (v *Values) extractValues(o Origin, data map[string]string) (map[string]interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if v.dataKey != "" {
+		rawData, ok := data[v.dataKey]
+		if !ok {
+			return nil, microerror.Maskf(parsingError, "expected %s to have key %#q but got keys %v", o, v.dataKey, slices.Sorted(maps.Keys(data)))
+		}
+
+		return parseValues(o, v.dataKey, rawData)
+	}
+
+	switch v.dataKeyMode {
+	case DataKeyModeMerge:
+		result := map[string]interface{}{}
+		for _, k := range slices.Sorted(maps.Keys(data)) {
+			values, err := parseValues(o, k, data[k])
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			err = mergo.Merge(&result, values, mergo.WithOverride)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+		}
+
+		return result, nil
+	case DataKeyModeNested:
+		result := map[string]interface{}{}
+		for k, rawData := range data {
+			var value interface{}
+			err := yaml.Unmarshal([]byte(rawData), &value)
+			if err != nil {
+				return nil, microerror.Mask(newYAMLSourceError(o, k, err))
+			}
+
+			result[k] = value
+		}
+
+		return result, nil
+	default:
+		if len(data) != 1 {
+			return nil, microerror.Maskf(parsingError, "expected %s has only one key but got keys %v", o, slices.Sorted(maps.Keys(data)))
+		}
+
+		for k, rawData := range data {
+			return parseValues(o, k, rawData)
+		}
+
+		return nil, nil
+	}
+}
+
+// parseValues parses the YAML values under the given key.
+func parseValues(o Origin, k, rawData string) (map[string]interface{}, error) {
+	var values map[string]interface{}
+
+	err := yaml.Unmarshal([]byte(rawData), &values)
+	if err != nil {
+		return nil, microerror.Mask(newYAMLSourceError(o, k, err))
+	}
+
+	return values, nil
+}
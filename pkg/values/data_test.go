@@ -0,0 +1,134 @@
+package values
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ExtractValues(t *testing.T) {
+	multiKeyData := map[string]string{
+		"b-ingress": "enabled: true\nhost: b\n",
+		"a-values":  "replicas: 2\nhost: a\n",
+	}
+
+	tests := []struct {
+		name         string
+		dataKey      string
+		dataKeyMode  string
+		data         map[string]string
+		expectedData map[string]interface{}
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: single key",
+			data: map[string]string{
+				"anything": "replicas: 2\n",
+			},
+			expectedData: map[string]interface{}{
+				"replicas": float64(2),
+			},
+		},
+		{
+			name:         "case 1: several keys without a data key or mode",
+			data:         multiKeyData,
+			errorMatcher: IsParsingError,
+		},
+		{
+			name:         "case 2: no keys",
+			data:         map[string]string{},
+			expectedData: nil,
+		},
+		{
+			name:    "case 3: data key",
+			dataKey: "a-values",
+			data:    multiKeyData,
+			expectedData: map[string]interface{}{
+				"replicas": float64(2),
+				"host":     "a",
+			},
+		},
+		{
+			name:         "case 4: missing data key",
+			dataKey:      "values",
+			data:         multiKeyData,
+			errorMatcher: IsParsingError,
+		},
+		{
+			name:        "case 5: merge keys in sorted order",
+			dataKeyMode: DataKeyModeMerge,
+			data:        multiKeyData,
+			expectedData: map[string]interface{}{
+				"enabled":  true,
+				"replicas": float64(2),
+				"host":     "b",
+			},
+		},
+		{
+			name:        "case 6: nest keys",
+			dataKeyMode: DataKeyModeNested,
+			data: map[string]string{
+				"ingress": "enabled: true\n",
+				"image":   "quay.io/giantswarm/test\n",
+			},
+			expectedData: map[string]interface{}{
+				"ingress": map[string]interface{}{
+					"enabled": true,
+				},
+				"image": "quay.io/giantswarm/test",
+			},
+		},
+		{
+			name:        "case 7: invalid YAML",
+			dataKeyMode: DataKeyModeMerge,
+			data: map[string]string{
+				"values": "- not\n- a map\n",
+			},
+			errorMatcher: IsParsingError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := New(Config{
+				K8sClient:   clientgofake.NewClientset(),
+				Logger:      microloggertest.New(),
+				DataKey:     tc.dataKey,
+				DataKeyMode: tc.dataKeyMode,
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			o := Origin{Kind: KindConfigMap, Name: "test-values", Namespace: "giantswarm", Layer: LayerUser}
+
+			result, err := v.extractValues(o, tc.data)
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expectedData) {
+				t.Fatalf("want matching data \n %s", cmp.Diff(result, tc.expectedData))
+			}
+		})
+	}
+}
+
+func Test_InvalidDataKeyMode(t *testing.T) {
+	_, err := New(Config{
+		K8sClient:   clientgofake.NewClientset(),
+		Logger:      microloggertest.New(),
+		DataKeyMode: "random",
+	})
+	if !IsInvalidConfig(err) {
+		t.Fatalf("error == %#v, want invalid config", err)
+	}
+}
@@ -0,0 +1,215 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+// ChangeType is the kind of change made to a single value.
+type ChangeType string
+
+const (
+	ChangeTypeAdded   ChangeType = "added"
+	ChangeTypeChanged ChangeType = "changed"
+	ChangeTypeRemoved ChangeType = "removed"
+	// ChangeTypeTypeChanged is a value replaced by a value of another type,
+	// e.g. the string "1" by the number 1.
+	ChangeTypeTypeChanged ChangeType = "type-changed"
+)
+
+// MaskedValue replaces values that come from secrets in changes.
+const MaskedValue = "*****"
+
+// Change describes the change of a single leaf of a set of values.
+type Change struct {
+	Type     ChangeType  `json:"type"`
+	Path     []string    `json:"path"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+}
+
+// DiffValues returns the leaf level changes needed to get from oldValues to
+// newValues, ordered by path. Maps replaced by other values and the other way
+// around are reported as their leaves being removed and added.
+func DiffValues(oldValues, newValues map[string]interface{}) []Change {
+	return diffValues(nil, oldValues, newValues)
+}
+
+// DiffApps resolves the values of two states of an app and its catalog the
+// same way MergeAll does and returns the changes between them, e.g. to
+// preview the effect of editing a user configmap. Values that come from
+// secrets are replaced by MaskedValue.
+func (v *Values) DiffApps(ctx context.Context, oldApp v1alpha1.App, oldCatalog v1alpha1.Catalog, newApp v1alpha1.App, newCatalog v1alpha1.Catalog) ([]Change, error) {
+	oldValues, oldProvenance, err := v.MergeAllWithProvenance(ctx, oldApp, oldCatalog)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	newValues, newProvenance, err := v.MergeAllWithProvenance(ctx, newApp, newCatalog)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	changes := diffValues(nil, oldValues, newValues)
+
+	for i, c := range changes {
+		if c.Type != ChangeTypeAdded && v.isSecretProvenance(oldProvenance.leafOf(c.Path)) {
+			changes[i].OldValue = MaskedValue
+		}
+		if c.Type != ChangeTypeRemoved && v.isSecretProvenance(newProvenance.leafOf(c.Path)) {
+			changes[i].NewValue = MaskedValue
+		}
+	}
+
+	return changes, nil
+}
+
+// diffValues returns the leaf level changes needed to get from oldData to
+// newData, ordered by path.
+func diffValues(path []string, oldData, newData map[string]interface{}) []Change {
+	var changes []Change
+
+	keys := map[string]bool{}
+	for k := range oldData {
+		keys[k] = true
+	}
+	for k := range newData {
+		keys[k] = true
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(keys)) {
+		childPath := appendPath(path, k)
+
+		oldValue, oldOK := oldData[k]
+		newValue, newOK := newData[k]
+
+		oldMap, oldIsMap := oldValue.(map[string]interface{})
+		newMap, newIsMap := newValue.(map[string]interface{})
+
+		switch {
+		case !oldOK:
+			changes = append(changes, leafChanges(ChangeTypeAdded, childPath, newValue)...)
+		case !newOK:
+			changes = append(changes, leafChanges(ChangeTypeRemoved, childPath, oldValue)...)
+		case oldIsMap && newIsMap:
+			changes = append(changes, diffValues(childPath, oldMap, newMap)...)
+		case oldIsMap || newIsMap:
+			changes = append(changes, leafChanges(ChangeTypeRemoved, childPath, oldValue)...)
+			changes = append(changes, leafChanges(ChangeTypeAdded, childPath, newValue)...)
+		case valueType(oldValue) != valueType(newValue):
+			changes = append(changes, Change{
+				Type:     ChangeTypeTypeChanged,
+				Path:     childPath,
+				OldValue: oldValue,
+				NewValue: newValue,
+			})
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, Change{
+				Type:     ChangeTypeChanged,
+				Path:     childPath,
+				OldValue: oldValue,
+				NewValue: newValue,
+			})
+		}
+	}
+
+	return changes
+}
+
+// leafChanges returns a change of the given type for every leaf of value.
+// Empty maps are considered leaves.
+func leafChanges(changeType ChangeType, path []string, value interface{}) []Change {
+	m, ok := value.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		c := Change{
+			Type: changeType,
+			Path: path,
+		}
+		if changeType == ChangeTypeRemoved {
+			c.OldValue = value
+		} else {
+			c.NewValue = value
+		}
+
+		return []Change{c}
+	}
+
+	var changes []Change
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		changes = append(changes, leafChanges(changeType, appendPath(path, k), m[k])...)
+	}
+
+	return changes
+}
+
+// valueType returns the JSON type of the given value.
+func valueType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, float32, int, int32, int64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// appendPath returns a copy of path with key appended so that paths handed
+// out never share their backing array.
+func appendPath(path []string, key string) []string {
+	childPath := make([]string, len(path), len(path)+1)
+	copy(childPath, path)
+
+	return append(childPath, key)
+}
+
+// deepCopyValues returns a copy of data that shares no maps or lists with
+// it.
+func deepCopyValues(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		result[k] = deepCopyValue(v)
+	}
+
+	return result
+}
+
+func deepCopyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return deepCopyValues(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i := range v {
+			result[i] = deepCopyValue(v[i])
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// formatPath returns the dotted notation of the given path, e.g.
+// `ingress.hosts`.
+func formatPath(path []string) string {
+	return strings.Join(path, ".")
+}
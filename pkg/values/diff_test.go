@@ -0,0 +1,143 @@
+package values
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DiffValues(t *testing.T) {
+	tests := []struct {
+		name            string
+		oldValues       map[string]interface{}
+		newValues       map[string]interface{}
+		expectedChanges []Change
+	}{
+		{
+			name: "case 0: no changes",
+			oldValues: map[string]interface{}{
+				"a": float64(1),
+			},
+			newValues: map[string]interface{}{
+				"a": float64(1),
+			},
+			expectedChanges: nil,
+		},
+		{
+			name: "case 1: added, removed, changed and type changed",
+			oldValues: map[string]interface{}{
+				"a": float64(1),
+				"b": map[string]interface{}{
+					"c": "old",
+					"d": "1",
+				},
+				"e": []interface{}{"x"},
+			},
+			newValues: map[string]interface{}{
+				"b": map[string]interface{}{
+					"c": "new",
+					"d": float64(1),
+				},
+				"e": []interface{}{"x"},
+				"f": true,
+			},
+			expectedChanges: []Change{
+				{Type: ChangeTypeRemoved, Path: []string{"a"}, OldValue: float64(1)},
+				{Type: ChangeTypeChanged, Path: []string{"b", "c"}, OldValue: "old", NewValue: "new"},
+				{Type: ChangeTypeTypeChanged, Path: []string{"b", "d"}, OldValue: "1", NewValue: float64(1)},
+				{Type: ChangeTypeAdded, Path: []string{"f"}, NewValue: true},
+			},
+		},
+		{
+			name: "case 2: map replaced by a value",
+			oldValues: map[string]interface{}{
+				"a": map[string]interface{}{
+					"b": "c",
+				},
+			},
+			newValues: map[string]interface{}{
+				"a": "b",
+			},
+			expectedChanges: []Change{
+				{Type: ChangeTypeRemoved, Path: []string{"a", "b"}, OldValue: "c"},
+				{Type: ChangeTypeAdded, Path: []string{"a"}, NewValue: "b"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := DiffValues(tc.oldValues, tc.newValues)
+
+			if !reflect.DeepEqual(changes, tc.expectedChanges) {
+				t.Fatalf("want matching changes \n %s", cmp.Diff(changes, tc.expectedChanges))
+			}
+		})
+	}
+}
+
+func Test_DiffApps(t *testing.T) {
+	k8sClient := clientgofake.NewClientset(
+		getConfigMapDefinition("test-user-values", "giantswarm", map[string]string{
+			"values": "replicas: 1\npassword: plain\n",
+		}),
+		getConfigMapDefinition("test-user-values-v2", "giantswarm", map[string]string{
+			"values": "replicas: 2\npassword: plain\n",
+		}),
+		getSecretDefinition("test-user-secrets", "giantswarm", map[string][]byte{
+			"values": []byte("password: secret\n"),
+		}),
+	)
+
+	v, err := New(Config{
+		K8sClient: k8sClient,
+		Logger:    microloggertest.New(),
+	})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	oldApp := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+					Name:      "test-user-values",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	newApp := *oldApp.DeepCopy()
+	newApp.Spec.UserConfig.ConfigMap.Name = "test-user-values-v2"
+	newApp.Spec.UserConfig.Secret = v1alpha1.AppSpecUserConfigSecret{
+		Name:      "test-user-secrets",
+		Namespace: "giantswarm",
+	}
+
+	changes, err := v.DiffApps(context.Background(), oldApp, v1alpha1.Catalog{}, newApp, v1alpha1.Catalog{})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	expectedChanges := []Change{
+		{Type: ChangeTypeChanged, Path: []string{"password"}, OldValue: "plain", NewValue: MaskedValue},
+		{Type: ChangeTypeChanged, Path: []string{"replicas"}, OldValue: float64(1), NewValue: float64(2)},
+	}
+	if !reflect.DeepEqual(changes, expectedChanges) {
+		t.Fatalf("want matching changes \n %s", cmp.Diff(changes, expectedChanges))
+	}
+}
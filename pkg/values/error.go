@@ -28,3 +28,48 @@ var parsingError = &microerror.Error{
 func IsParsingError(err error) bool {
 	return microerror.Cause(err) == parsingError
 }
+
+var executionFailedError = &microerror.Error{
+	Kind: "executionFailedError",
+}
+
+// IsExecutionFailed asserts executionFailedError.
+func IsExecutionFailed(err error) bool {
+	return microerror.Cause(err) == executionFailedError
+}
+
+var unknownKindError = &microerror.Error{
+	Kind: "unknownKindError",
+}
+
+// IsUnknownKind asserts unknownKindError.
+func IsUnknownKind(err error) bool {
+	return microerror.Cause(err) == unknownKindError
+}
+
+var interpolationFailedError = &microerror.Error{
+	Kind: "interpolationFailedError",
+}
+
+// IsInterpolationFailed asserts interpolationFailedError.
+func IsInterpolationFailed(err error) bool {
+	return microerror.Cause(err) == interpolationFailedError
+}
+
+var invalidReferenceError = &microerror.Error{
+	Kind: "invalidReferenceError",
+}
+
+// IsInvalidReference asserts invalidReferenceError.
+func IsInvalidReference(err error) bool {
+	return microerror.Cause(err) == invalidReferenceError
+}
+
+var limitExceededError = &microerror.Error{
+	Kind: "limitExceededError",
+}
+
+// IsLimitExceeded asserts limitExceededError.
+func IsLimitExceeded(err error) bool {
+	return microerror.Cause(err) == limitExceededError
+}
@@ -0,0 +1,206 @@
+package values
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	ExplanationFormatJSON     = "json"
+	ExplanationFormatMarkdown = "markdown"
+	ExplanationFormatText     = "text"
+)
+
+// Explanation describes how a set of values was built, layer by layer.
+type Explanation struct {
+	Layers []LayerExplanation `json:"layers"`
+}
+
+// LayerExplanation lists what a single layer added, changed or removed in
+// the values merged before it.
+type LayerExplanation struct {
+	Origin  Origin   `json:"origin"`
+	Changes []Change `json:"changes"`
+}
+
+// ExplainConfigMapData merges the configmap values the same way
+// MergeConfigMapData does and reports the changes made by every layer in
+// merge order.
+func (v *Values) ExplainConfigMapData(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (*Explanation, error) {
+	origins, err := v.configMapOrigins(app, catalog)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	layers, err := v.fetchLayers(ctx, app, origins)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	explanation, err := v.explainLayers(ctx, layers)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return explanation, nil
+}
+
+func (v *Values) explainLayers(ctx context.Context, layers []layer) (*Explanation, error) {
+	explanation := &Explanation{
+		Layers: []LayerExplanation{},
+	}
+
+	data := map[string]interface{}{}
+
+	for _, l := range layers {
+		if l.skipped {
+			continue
+		}
+
+		before := deepCopyValues(data)
+		pruneDeleted(before)
+
+		err := v.mergeLayer(ctx, data, l, nil)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		after := deepCopyValues(data)
+		pruneDeleted(after)
+
+		changes := diffValues(nil, before, after)
+		if changes == nil {
+			changes = []Change{}
+		}
+
+		explanation.Layers = append(explanation.Layers, LayerExplanation{
+			Origin:  l.origin,
+			Changes: changes,
+		})
+	}
+
+	return explanation, nil
+}
+
+// MarshalExplanation renders the given explanation in one of the
+// ExplanationFormat* formats.
+func MarshalExplanation(explanation *Explanation, format string) (string, error) {
+	switch format {
+	case ExplanationFormatJSON:
+		output, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return string(output) + "\n", nil
+	case ExplanationFormatMarkdown:
+		return marshalExplanationMarkdown(explanation), nil
+	case ExplanationFormatText:
+		return marshalExplanationText(explanation), nil
+	default:
+		return "", microerror.Maskf(invalidConfigError, "format must be one of %#q, %#q or %#q but got %#q", ExplanationFormatJSON, ExplanationFormatMarkdown, ExplanationFormatText, format)
+	}
+}
+
+// PrintExplanation writes the given explanation to w in one of the
+// ExplanationFormat* formats.
+func PrintExplanation(w io.Writer, format string, explanation *Explanation) error {
+	output, err := MarshalExplanation(explanation, format)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	_, err = fmt.Fprintf(w, "%s", output)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func marshalExplanationText(explanation *Explanation) string {
+	var b bytes.Buffer
+
+	for i, l := range explanation.Layers {
+		fmt.Fprintf(&b, "%d. %s, priority %d\n", i+1, l.Origin, l.Origin.Priority)
+
+		if len(l.Changes) == 0 {
+			fmt.Fprintf(&b, "   (no changes)\n")
+		}
+
+		for _, c := range l.Changes {
+			switch c.Type {
+			case ChangeTypeAdded:
+				fmt.Fprintf(&b, "   %-8s %s: %s\n", c.Type, formatPath(c.Path), formatValue(c.NewValue))
+			case ChangeTypeRemoved:
+				fmt.Fprintf(&b, "   %-8s %s: %s\n", c.Type, formatPath(c.Path), formatValue(c.OldValue))
+			default:
+				fmt.Fprintf(&b, "   %-8s %s: %s -> %s\n", c.Type, formatPath(c.Path), formatValue(c.OldValue), formatValue(c.NewValue))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func marshalExplanationMarkdown(explanation *Explanation) string {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "# Values explanation\n")
+
+	for i, l := range explanation.Layers {
+		fmt.Fprintf(&b, "\n## %d. %s, priority %d\n\n", i+1, l.Origin, l.Origin.Priority)
+
+		if len(l.Changes) == 0 {
+			fmt.Fprintf(&b, "_No changes._\n")
+			continue
+		}
+
+		fmt.Fprintf(&b, "| Change | Path | Old value | New value |\n")
+		fmt.Fprintf(&b, "| --- | --- | --- | --- |\n")
+
+		for _, c := range l.Changes {
+			oldValue := ""
+			if c.Type != ChangeTypeAdded {
+				oldValue = markdownCode(formatValue(c.OldValue))
+			}
+			newValue := ""
+			if c.Type != ChangeTypeRemoved {
+				newValue = markdownCode(formatValue(c.NewValue))
+			}
+
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", c.Type, markdownCode(formatPath(c.Path)), oldValue, newValue)
+		}
+	}
+
+	return b.String()
+}
+
+// formatValue returns the compact JSON representation of a single value.
+func formatValue(value interface{}) string {
+	output, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(output)
+}
+
+// markdownCode returns s as inline code that is safe to use in a table
+// cell.
+func markdownCode(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+
+	if strings.Contains(s, "`") {
+		return s
+	}
+
+	return "`" + s + "`"
+}
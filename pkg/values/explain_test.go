@@ -0,0 +1,128 @@
+package values
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ExplainConfigMapData(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+				},
+			},
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+					Name:      "test-user-values",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	k8sClient := clientgofake.NewClientset(
+		getTestCatalogConfigMapDefinition(map[string]string{
+			"values": "a: 1\nb:\n  c: catalog\n",
+		}),
+		getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{
+			"values": "a: 2\nb: flat\n",
+		}),
+		getConfigMapDefinition("test-user-values", "giantswarm", map[string]string{
+			"values": "a: 2\n",
+		}),
+	)
+
+	v, err := New(Config{
+		K8sClient: k8sClient,
+		Logger:    microloggertest.New(),
+	})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	explanation, err := v.ExplainConfigMapData(context.Background(), app, getSimpleTestCatalogDefinitionWithConfigMap())
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	tests := []struct {
+		name           string
+		format         string
+		expectedOutput string
+		errorMatcher   func(error) bool
+	}{
+		{
+			name:   "case 0: text",
+			format: ExplanationFormatText,
+			expectedOutput: "1. catalog configMap `test-catalog-values` in namespace `giantswarm`, priority 0\n" +
+				"   added    a: 1\n" +
+				"   added    b.c: \"catalog\"\n" +
+				"2. app configMap `test-cluster-values` in namespace `giantswarm`, priority 50\n" +
+				"   changed  a: 1 -> 2\n" +
+				"   removed  b.c: \"catalog\"\n" +
+				"   added    b: \"flat\"\n" +
+				"3. user configMap `test-user-values` in namespace `giantswarm`, priority 100\n" +
+				"   (no changes)\n",
+		},
+		{
+			name:   "case 1: markdown",
+			format: ExplanationFormatMarkdown,
+			expectedOutput: "# Values explanation\n" +
+				"\n## 1. catalog configMap `test-catalog-values` in namespace `giantswarm`, priority 0\n\n" +
+				"| Change | Path | Old value | New value |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| added | `a` |  | `1` |\n" +
+				"| added | `b.c` |  | `\"catalog\"` |\n" +
+				"\n## 2. app configMap `test-cluster-values` in namespace `giantswarm`, priority 50\n\n" +
+				"| Change | Path | Old value | New value |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| changed | `a` | `1` | `2` |\n" +
+				"| removed | `b.c` | `\"catalog\"` |  |\n" +
+				"| added | `b` |  | `\"flat\"` |\n" +
+				"\n## 3. user configMap `test-user-values` in namespace `giantswarm`, priority 100\n\n" +
+				"_No changes._\n",
+		},
+		{
+			name:         "case 2: unknown format",
+			format:       "html",
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			err := PrintExplanation(&b, tc.format, explanation)
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if b.String() != tc.expectedOutput {
+				t.Fatalf("want matching output \n %s", cmp.Diff(b.String(), tc.expectedOutput))
+			}
+		})
+	}
+}
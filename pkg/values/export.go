@@ -0,0 +1,199 @@
+package values
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ValuesFileName is the name of the file holding the values not read
+	// from secrets.
+	ValuesFileName = "values.yaml"
+	// SecretValuesFileName is the name of the file holding the values read
+	// from secrets.
+	SecretValuesFileName = "secret-values.yaml"
+)
+
+// HelmValues holds merged values as YAML files to be passed to Helm in
+// order, e.g. `helm template -f values.yaml -f secret-values.yaml`. Keys are
+// sorted so the files only change when the values do.
+type HelmValues struct {
+	// Values holds the values not read from secrets.
+	Values []byte
+	// SecretValues holds the values read from secrets.
+	SecretValues []byte
+}
+
+// ExportHelmValues merges the values like MergeAll does and returns them as
+// Helm values files. When header is set the files start with a comment
+// listing the objects their values were read from.
+func (v *Values) ExportHelmValues(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog, header bool) (*HelmValues, error) {
+	data, provenance, err := v.MergeAllWithProvenance(ctx, app, catalog)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	helmValues, err := v.NewHelmValues(data, provenance, header)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return helmValues, nil
+}
+
+// NewHelmValues splits the given output of MergeAllWithProvenance into Helm
+// values files. Values read from secrets go to SecretValues, all other values
+// to Values. When header is set the files start with a comment listing the
+// objects their values were read from. Without provenance values cannot be
+// told apart, so it fails with invalidConfigError when provenance is nil.
+func (v *Values) NewHelmValues(data map[string]interface{}, provenance *Provenance, header bool) (*HelmValues, error) {
+	if provenance == nil {
+		return nil, microerror.Maskf(invalidConfigError, "provenance must not be empty")
+	}
+
+	split := v.splitSecretValues(data, provenance)
+
+	h := &HelmValues{}
+
+	var err error
+	h.Values, err = marshalHelmValues(split.values, split.origins, header)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	h.SecretValues, err = marshalHelmValues(split.secretValues, split.secretOrigins, header)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return h, nil
+}
+
+// secretSplit is a set of merged values split into the values not read from
+// secrets and the values read from secrets.
+type secretSplit struct {
+	values        map[string]interface{}
+	secretValues  map[string]interface{}
+	origins       []Origin
+	secretOrigins []Origin
+}
+
+// splitSecretValues splits the given merged values by the given provenance.
+// Values are secret when anything they were made of or overrode comes from a
+// secret, see isSecretProvenance. The values are copied. The origins of the
+// values of both parts are returned too.
+func (v *Values) splitSecretValues(data map[string]interface{}, provenance *Provenance) secretSplit {
+	split := secretSplit{
+		values:       deepCopyValues(data),
+		secretValues: map[string]interface{}{},
+	}
+	if split.values == nil {
+		split.values = map[string]interface{}{}
+	}
+
+	provenance.Walk(func(path []string, leaf *Provenance) {
+		if leaf.Origin == nil {
+			return
+		}
+
+		if v.isSecretProvenance(leaf) {
+			moveValue(split.secretValues, split.values, path)
+			split.secretOrigins = append(split.secretOrigins, leaf.origins()...)
+		} else {
+			split.origins = append(split.origins, leaf.origins()...)
+		}
+	})
+
+	return split
+}
+
+// WriteFiles writes ValuesFileName and SecretValuesFileName into the given
+// directory. Both files are only readable by their owner.
+func (h *HelmValues) WriteFiles(dir string) error {
+	err := os.WriteFile(filepath.Join(dir, ValuesFileName), h.Values, 0600)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, SecretValuesFileName), h.SecretValues, 0600)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// marshalHelmValues encodes the given values as YAML with sorted keys,
+// optionally preceded by a comment listing the given origins in merge order.
+func marshalHelmValues(values map[string]interface{}, origins []Origin, header bool) ([]byte, error) {
+	raw, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if !header {
+		return raw, nil
+	}
+
+	slices.SortFunc(origins, func(a, b Origin) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.String(), b.String()))
+	})
+	origins = slices.Compact(origins)
+
+	var buf bytes.Buffer
+	if len(origins) == 0 {
+		buf.WriteString("# No values.\n")
+	} else {
+		buf.WriteString("# Values read from:\n")
+		for _, o := range origins {
+			fmt.Fprintf(&buf, "#   - %s\n", o)
+		}
+	}
+	buf.Write(raw)
+
+	return buf.Bytes(), nil
+}
+
+// moveValue moves the value at the given path from src to dst, creating the
+// maps leading to it in dst. Maps of src left empty by the move are removed.
+func moveValue(dst, src map[string]interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+
+	k := path[0]
+
+	if len(path) == 1 {
+		value, ok := src[k]
+		if ok {
+			dst[k] = value
+			delete(src, k)
+		}
+		return
+	}
+
+	srcNested, ok := src[k].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	dstNested, ok := dst[k].(map[string]interface{})
+	if !ok {
+		dstNested = map[string]interface{}{}
+		dst[k] = dstNested
+	}
+
+	moveValue(dstNested, srcNested, path[1:])
+
+	if len(srcNested) == 0 {
+		delete(src, k)
+	}
+}
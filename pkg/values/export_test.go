@@ -0,0 +1,235 @@
+package values
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ExportHelmValues(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+				},
+			},
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				Secret: v1alpha1.AppSpecUserConfigSecret{
+					Name:      "test-user-secrets",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		header               bool
+		expectedValues       string
+		expectedSecretValues string
+	}{
+		{
+			name:                 "case 0: without header",
+			expectedValues:       "replicas: 2\nzone: a\n",
+			expectedSecretValues: "auth:\n  password: secret\n",
+		},
+		{
+			name:   "case 1: with header",
+			header: true,
+			expectedValues: "# Values read from:\n" +
+				"#   - app configMap `test-cluster-values` in namespace `giantswarm`\n" +
+				"replicas: 2\nzone: a\n",
+			expectedSecretValues: "# Values read from:\n" +
+				"#   - user secret `test-user-secrets` in namespace `giantswarm`\n" +
+				"auth:\n  password: secret\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClient := clientgofake.NewClientset(
+				getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{
+					"values": "zone: a\nreplicas: 2\nauth:\n  password: changeme\n",
+				}),
+				getSecretDefinition("test-user-secrets", "giantswarm", map[string][]byte{
+					"values": []byte("auth:\n  password: secret\n"),
+				}),
+			)
+
+			v, err := New(Config{
+				K8sClient: k8sClient,
+				Logger:    microloggertest.New(),
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			helmValues, err := v.ExportHelmValues(context.Background(), app, v1alpha1.Catalog{}, tc.header)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			dir := t.TempDir()
+
+			err = helmValues.WriteFiles(dir)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			values, err := os.ReadFile(filepath.Join(dir, ValuesFileName))
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+			if string(values) != tc.expectedValues {
+				t.Fatalf("want matching values \n %s", cmp.Diff(string(values), tc.expectedValues))
+			}
+
+			secretValues, err := os.ReadFile(filepath.Join(dir, SecretValuesFileName))
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+			if string(secretValues) != tc.expectedSecretValues {
+				t.Fatalf("want matching secret values \n %s", cmp.Diff(string(secretValues), tc.expectedSecretValues))
+			}
+		})
+	}
+
+	t.Run("missing provenance", func(t *testing.T) {
+		v, err := New(Config{
+			K8sClient: clientgofake.NewClientset(),
+			Logger:    microloggertest.New(),
+		})
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+
+		_, err = v.NewHelmValues(map[string]interface{}{"a": "b"}, nil, false)
+		if !IsInvalidConfig(err) {
+			t.Fatalf("error == %#v, want invalid config", err)
+		}
+	})
+}
+
+func Test_ExportHelmValuesWithReferenceInSecretList(t *testing.T) {
+	ctx := context.Background()
+
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+				},
+			},
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				Secret: v1alpha1.AppSpecUserConfigSecret{
+					Name:      "test-user-secrets",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	secretValues := func(secret string) map[string][]byte {
+		return map[string][]byte{
+			"values": []byte("hosts:\n- " + secret + "\n- valueFrom:\n    configMapKeyRef:\n      name: shared\n      key: host\n"),
+		}
+	}
+
+	newValues := func(t *testing.T, secret string) *Values {
+		t.Helper()
+
+		v, err := New(Config{
+			K8sClient: clientgofake.NewClientset(
+				getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{
+					"values": "replicas: 2\n",
+				}),
+				getConfigMapDefinition("shared", "giantswarm", map[string]string{
+					"host": "example.com",
+				}),
+				getSecretDefinition("test-user-secrets", "giantswarm", secretValues(secret)),
+				getSecretDefinition("test-other-user-secrets", "giantswarm", secretValues("OTHERSECRET")),
+			),
+			Logger:                 microloggertest.New(),
+			ResolveReferences:      true,
+			SeparateSecretChecksum: true,
+		})
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+
+		return v
+	}
+
+	// The list read from the secret stays secret although one of its
+	// entries is read from a configmap.
+	helmValues, err := newValues(t, "SUPERSECRET").ExportHelmValues(ctx, app, v1alpha1.Catalog{}, false)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	expectedValues := "replicas: 2\n"
+	if string(helmValues.Values) != expectedValues {
+		t.Fatalf("want matching values \n %s", cmp.Diff(string(helmValues.Values), expectedValues))
+	}
+	expectedSecretValues := "hosts:\n- SUPERSECRET\n- example.com\n"
+	if string(helmValues.SecretValues) != expectedSecretValues {
+		t.Fatalf("want matching secret values \n %s", cmp.Diff(string(helmValues.SecretValues), expectedSecretValues))
+	}
+
+	// Only the secrets checksum depends on the secret.
+	_, checksumsA, err := newValues(t, "SUPERSECRET").MergeAllWithChecksums(ctx, app, v1alpha1.Catalog{})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	_, checksumsB, err := newValues(t, "OTHERSECRET").MergeAllWithChecksums(ctx, app, v1alpha1.Catalog{})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	if checksumsA.Values != checksumsB.Values {
+		t.Fatalf("want values checksum to not depend on the secret")
+	}
+	if checksumsA.Secrets == checksumsB.Secrets {
+		t.Fatalf("want secrets checksum to change with the secret")
+	}
+
+	// Diffs mask the list.
+	newApp := *app.DeepCopy()
+	newApp.Spec.UserConfig.Secret.Name = "test-other-user-secrets"
+
+	changes, err := newValues(t, "SUPERSECRET").DiffApps(ctx, app, v1alpha1.Catalog{}, newApp, v1alpha1.Catalog{})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	if len(changes) == 0 {
+		t.Fatalf("changes == %#v, want some", changes)
+	}
+	for _, c := range changes {
+		if c.OldValue != MaskedValue || c.NewValue != MaskedValue {
+			t.Fatalf("change == %#v, want masked values", c)
+		}
+	}
+}
@@ -14,20 +14,21 @@ func isWithinPriorityLevel(minExclusive, maxInclusive int) func(priority int) bo
 	}
 }
 
-func getExtraConfigs(appExtraConfigs []v1alpha1.AppExtraConfig, kind string, priorityCondition func(int) bool) []v1alpha1.AppExtraConfig {
+func isKind(kind string) func(extraConfigKind string) bool {
+	return func(extraConfigKind string) bool {
+		return extraConfigKind == kind
+	}
+}
+
+func getExtraConfigs(appExtraConfigs []v1alpha1.AppExtraConfig, kindCondition func(string) bool, priorityCondition func(int) bool) []v1alpha1.AppExtraConfig {
 	extraConfigs := []v1alpha1.AppExtraConfig{}
 
 	for _, extraConfig := range appExtraConfigs {
-		var extraConfigKind string
-		if extraConfig.Kind != "" {
-			extraConfigKind = extraConfig.Kind
-		} else {
-			extraConfigKind = "configMap"
-		}
+		var extraConfigKind = considerDefaultKind(extraConfig.Kind)
 
 		var extraConfigPriority = considerDefaultPriority(extraConfig.Priority)
 
-		if extraConfigKind == kind && priorityCondition(extraConfigPriority) {
+		if kindCondition(extraConfigKind) && priorityCondition(extraConfigPriority) {
 			extraConfigs = append(extraConfigs, extraConfig)
 		}
 	}
@@ -42,6 +43,15 @@ func getExtraConfigs(appExtraConfigs []v1alpha1.AppExtraConfig, kind string, pri
 
 	return extraConfigs
 }
+
+func considerDefaultKind(value string) string {
+	if value != "" {
+		return value
+	} else {
+		return KindConfigMap
+	}
+}
+
 func considerDefaultPriority(value int) int {
 	if value != 0 {
 		return value
@@ -54,46 +64,22 @@ func considerDefaultPriority(value int) int {
 
 var isPreClusterPriority = isWithinPriorityLevel(v1alpha1.ConfigPriorityCatalog, v1alpha1.ConfigPriorityCluster)
 
-func getPreClusterExtraConfigs(appExtraConfigs []v1alpha1.AppExtraConfig, kind string) []v1alpha1.AppExtraConfig {
-	return getExtraConfigs(appExtraConfigs, kind, isPreClusterPriority)
-}
-
-func getPreClusterExtraConfigMapEntries(appExtraConfigs []v1alpha1.AppExtraConfig) []v1alpha1.AppExtraConfig {
-	return getPreClusterExtraConfigs(appExtraConfigs, "configMap")
-}
-
-func getPreClusterExtraSecretEntries(appExtraConfigs []v1alpha1.AppExtraConfig) []v1alpha1.AppExtraConfig {
-	return getPreClusterExtraConfigs(appExtraConfigs, "secret")
+func getPreClusterExtraConfigs(appExtraConfigs []v1alpha1.AppExtraConfig, kindCondition func(string) bool) []v1alpha1.AppExtraConfig {
+	return getExtraConfigs(appExtraConfigs, kindCondition, isPreClusterPriority)
 }
 
 // Post Cluster + Pre User
 
 var isPostClusterPreUserPriority = isWithinPriorityLevel(v1alpha1.ConfigPriorityCluster, v1alpha1.ConfigPriorityUser)
 
-func getPostClusterPreUserExtraConfigs(appExtraConfigs []v1alpha1.AppExtraConfig, kind string) []v1alpha1.AppExtraConfig {
-	return getExtraConfigs(appExtraConfigs, kind, isPostClusterPreUserPriority)
-}
-
-func getPostClusterPreUserExtraConfigMapEntries(appExtraConfigs []v1alpha1.AppExtraConfig) []v1alpha1.AppExtraConfig {
-	return getPostClusterPreUserExtraConfigs(appExtraConfigs, "configMap")
-}
-
-func getPostClusterPreUserExtraSecretEntries(appExtraConfigs []v1alpha1.AppExtraConfig) []v1alpha1.AppExtraConfig {
-	return getPostClusterPreUserExtraConfigs(appExtraConfigs, "secret")
+func getPostClusterPreUserExtraConfigs(appExtraConfigs []v1alpha1.AppExtraConfig, kindCondition func(string) bool) []v1alpha1.AppExtraConfig {
+	return getExtraConfigs(appExtraConfigs, kindCondition, isPostClusterPreUserPriority)
 }
 
 // Post User
 
 var isPostUserPriority = isWithinPriorityLevel(v1alpha1.ConfigPriorityUser, v1alpha1.ConfigPriorityMaximum)
 
-func getPostUserExtraConfigs(appExtraConfigs []v1alpha1.AppExtraConfig, kind string) []v1alpha1.AppExtraConfig {
-	return getExtraConfigs(appExtraConfigs, kind, isPostUserPriority)
-}
-
-func getPostUserExtraConfigMapEntries(appExtraConfigs []v1alpha1.AppExtraConfig) []v1alpha1.AppExtraConfig {
-	return getPostUserExtraConfigs(appExtraConfigs, "configMap")
-}
-
-func getPostUserExtraSecretEntries(appExtraConfigs []v1alpha1.AppExtraConfig) []v1alpha1.AppExtraConfig {
-	return getPostUserExtraConfigs(appExtraConfigs, "secret")
+func getPostUserExtraConfigs(appExtraConfigs []v1alpha1.AppExtraConfig, kindCondition func(string) bool) []v1alpha1.AppExtraConfig {
+	return getExtraConfigs(appExtraConfigs, kindCondition, isPostUserPriority)
 }
@@ -12,13 +12,15 @@ func Test_GetExtraConfigs(t *testing.T) {
 	tests := []struct {
 		name           string
 		appExtraConfig []v1alpha1.AppExtraConfig
-		method         func([]v1alpha1.AppExtraConfig) []v1alpha1.AppExtraConfig
+		method         func([]v1alpha1.AppExtraConfig, func(string) bool) []v1alpha1.AppExtraConfig
+		kindCondition  func(string) bool
 		expected       []v1alpha1.AppExtraConfig
 	}{
 		{
 			"Empty list",
 			[]v1alpha1.AppExtraConfig{},
-			getPreClusterExtraConfigMapEntries,
+			getPreClusterExtraConfigs,
+			isKind(KindConfigMap),
 			[]v1alpha1.AppExtraConfig{},
 		},
 		{
@@ -27,7 +29,8 @@ func Test_GetExtraConfigs(t *testing.T) {
 				{Name: "test-config-map-1", Namespace: "default"},
 				{Kind: "secret", Name: "test-secret-1", Namespace: "default"},
 			},
-			getPreClusterExtraConfigMapEntries,
+			getPreClusterExtraConfigs,
+			isKind(KindConfigMap),
 			[]v1alpha1.AppExtraConfig{
 				{Name: "test-config-map-1", Namespace: "default"},
 			},
@@ -38,7 +41,8 @@ func Test_GetExtraConfigs(t *testing.T) {
 				{Name: "test-config-map-1", Namespace: "default"},
 				{Kind: "secret", Name: "test-secret-1", Namespace: "default"},
 			},
-			getPreClusterExtraSecretEntries,
+			getPreClusterExtraConfigs,
+			isKind(KindSecret),
 			[]v1alpha1.AppExtraConfig{
 				{Kind: "secret", Name: "test-secret-1", Namespace: "default"},
 			},
@@ -54,7 +58,8 @@ func Test_GetExtraConfigs(t *testing.T) {
 				{Name: "test-config-map-5", Namespace: "default", Priority: v1alpha1.ConfigPriorityUser},
 				{Name: "test-config-map-7", Namespace: "default"},
 			},
-			getPreClusterExtraConfigMapEntries,
+			getPreClusterExtraConfigs,
+			isKind(KindConfigMap),
 			[]v1alpha1.AppExtraConfig{
 				{Name: "test-config-map-4", Namespace: "default", Priority: v1alpha1.ConfigPriorityDefault},
 				{Name: "test-config-map-7", Namespace: "default"},
@@ -75,7 +80,8 @@ func Test_GetExtraConfigs(t *testing.T) {
 				{Name: "test-config-map-7", Namespace: "default", Priority: v1alpha1.ConfigPriorityUser - 1},
 				{Name: "test-config-map-8", Namespace: "default", Priority: v1alpha1.ConfigPriorityUser + 1},
 			},
-			getPostClusterPreUserExtraConfigMapEntries,
+			getPostClusterPreUserExtraConfigs,
+			isKind(KindConfigMap),
 			[]v1alpha1.AppExtraConfig{
 				{Name: "test-config-map-3", Namespace: "default", Priority: v1alpha1.ConfigPriorityCluster + 1},
 				{Name: "test-config-map-4", Namespace: "default", Priority: v1alpha1.ConfigPriorityCluster + v1alpha1.ConfigPriorityDistance/2},
@@ -96,7 +102,8 @@ func Test_GetExtraConfigs(t *testing.T) {
 				{Kind: "secret", Name: "test-secret-6", Priority: v1alpha1.ConfigPriorityUser - 1},
 				{Kind: "secret", Name: "test-secret-7", Priority: v1alpha1.ConfigPriorityUser + 1},
 			},
-			getPostClusterPreUserExtraSecretEntries,
+			getPostClusterPreUserExtraConfigs,
+			isKind(KindSecret),
 			[]v1alpha1.AppExtraConfig{
 				{Kind: "secret", Name: "test-secret-2", Priority: v1alpha1.ConfigPriorityCluster + 1},
 				{Kind: "secret", Name: "test-secret-5", Namespace: "default", Priority: v1alpha1.ConfigPriorityCluster + v1alpha1.ConfigPriorityDistance/2},
@@ -116,7 +123,8 @@ func Test_GetExtraConfigs(t *testing.T) {
 				{Name: "test-config-map-5", Namespace: "default", Priority: v1alpha1.ConfigPriorityUser - 1},
 				{Name: "test-config-map-6", Namespace: "default", Priority: v1alpha1.ConfigPriorityUser + 1},
 			},
-			getPostUserExtraConfigMapEntries,
+			getPostUserExtraConfigs,
+			isKind(KindConfigMap),
 			[]v1alpha1.AppExtraConfig{
 				{Name: "test-config-map-6", Namespace: "default", Priority: v1alpha1.ConfigPriorityUser + 1},
 				{Name: "test-config-map-3", Namespace: "default", Priority: v1alpha1.ConfigPriorityUser + v1alpha1.ConfigPriorityDistance/2},
@@ -136,7 +144,8 @@ func Test_GetExtraConfigs(t *testing.T) {
 				{Kind: "secret", Name: "test-secret-6", Namespace: "default", Priority: v1alpha1.ConfigPriorityUser - 1},
 				{Kind: "secret", Name: "test-secret-7", Namespace: "default", Priority: v1alpha1.ConfigPriorityUser + 1},
 			},
-			getPostUserExtraSecretEntries,
+			getPostUserExtraConfigs,
+			isKind(KindSecret),
 			[]v1alpha1.AppExtraConfig{
 				{Kind: "secret", Name: "test-secret-7", Namespace: "default", Priority: v1alpha1.ConfigPriorityUser + 1},
 				{Kind: "secret", Name: "test-secret-3", Namespace: "default", Priority: v1alpha1.ConfigPriorityUser + v1alpha1.ConfigPriorityDistance/2},
@@ -149,7 +158,7 @@ func Test_GetExtraConfigs(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := tc.method(tc.appExtraConfig)
+			result := tc.method(tc.appExtraConfig, tc.kindCondition)
 
 			if !reflect.DeepEqual(result, tc.expected) {
 				t.Fatalf("want matching data \n %s", cmp.Diff(result, tc.expected))
@@ -0,0 +1,170 @@
+package values
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/app/v8/pkg/key"
+)
+
+const (
+	// interpolationLeftDelim and interpolationRightDelim enclose
+	// placeholders. They differ from the default delimiters of Go templates,
+	// so that values rendered by charts themselves, e.g. with `tpl`, like
+	// `{{ .Release.Name }}` or Prometheus alerts like
+	// `{{ $labels.instance }}`, are left untouched.
+	interpolationLeftDelim  = "${{"
+	interpolationRightDelim = "}}"
+)
+
+// InterpolationContext is the data placeholders in values are filled from
+// when Config.Interpolate is set, e.g. `${{ .ClusterID }}` or
+// `${{ .App.Namespace }}`.
+type InterpolationContext struct {
+	// ClusterID is the ID of the cluster the app is installed in.
+	ClusterID string
+	// Organization is the organization owning the cluster.
+	Organization string
+	App          InterpolationApp
+}
+
+// InterpolationApp is the app related data of InterpolationContext.
+type InterpolationApp struct {
+	// Name is the name of the app in the catalog.
+	Name string
+	// Namespace is the namespace the app is installed in.
+	Namespace string
+	// Version is the version of the app.
+	Version string
+}
+
+// NewInterpolationContext returns the interpolation context of the given app.
+func NewInterpolationContext(app v1alpha1.App) InterpolationContext {
+	return InterpolationContext{
+		ClusterID:    key.ClusterID(app),
+		Organization: key.OrganizationID(app),
+		App: InterpolationApp{
+			Name:      key.AppName(app),
+			Namespace: key.Namespace(app),
+			Version:   key.Version(app),
+		},
+	}
+}
+
+// interpolate fills the placeholders in all string values of data from the
+// context of the given app, modifying data inplace. Nothing is done unless
+// Config.Interpolate is set. Placeholders have the syntax of Go templates
+// with `${{` and `}}` as delimiters but may only be fields of
+// InterpolationContext like `${{ .App.Name }}`. Anything else, e.g. a missing
+// field, a function call, a pipeline or a control structure, fails with
+// interpolationFailedError naming the value. Go templates with the default
+// delimiters are not interpolated.
+func (v *Values) interpolate(app v1alpha1.App, data map[string]interface{}) error {
+	if !v.interpolation {
+		return nil
+	}
+
+	err := interpolateValues(nil, data, NewInterpolationContext(app))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func interpolateValues(path []string, data map[string]interface{}, context InterpolationContext) error {
+	for k, value := range data {
+		interpolated, err := interpolateValue(appendPath(path, k), value, context)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		data[k] = interpolated
+	}
+
+	return nil
+}
+
+func interpolateValue(path []string, value interface{}, context InterpolationContext) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		err := interpolateValues(path, v, context)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		return v, nil
+	case []interface{}:
+		for i := range v {
+			interpolated, err := interpolateValue(appendPath(path, strconv.Itoa(i)), v[i], context)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			v[i] = interpolated
+		}
+		return v, nil
+	case string:
+		interpolated, err := interpolateString(v, context)
+		if err != nil {
+			return nil, microerror.Maskf(interpolationFailedError, "failed to interpolate value %#q, logs: %s", formatPath(path), err.Error())
+		}
+		return interpolated, nil
+	default:
+		return v, nil
+	}
+}
+
+func interpolateString(s string, context InterpolationContext) (string, error) {
+	if !strings.Contains(s, interpolationLeftDelim) {
+		return s, nil
+	}
+
+	t, err := template.New("value").Delims(interpolationLeftDelim, interpolationRightDelim).Option("missingkey=error").Parse(s)
+	if err != nil {
+		return "", err
+	}
+
+	// Built-in template functions like printf could expand small values
+	// into huge ones, so templates are restricted to plain field access.
+	// Unknown fields fail template execution.
+	if len(t.Templates()) > 1 {
+		return "", microerror.Maskf(interpolationFailedError, "only placeholders like `${{ .ClusterID }}` are allowed, not template definitions")
+	}
+	for _, node := range t.Root.Nodes {
+		err = checkPlaceholder(node)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+	}
+
+	var b bytes.Buffer
+	err = t.Execute(&b, context)
+	if err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// checkPlaceholder returns an error unless the given top level node of a
+// template is either text or a placeholder reading a single field like
+// `${{ .App.Name }}`.
+func checkPlaceholder(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.TextNode:
+		return nil
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) == 0 && len(n.Pipe.Cmds) == 1 && len(n.Pipe.Cmds[0].Args) == 1 {
+			if _, ok := n.Pipe.Cmds[0].Args[0].(*parse.FieldNode); ok {
+				return nil
+			}
+		}
+	}
+
+	return microerror.Maskf(interpolationFailedError, "only placeholders like `${{ .ClusterID }}` are allowed, got %#q", node.String())
+}
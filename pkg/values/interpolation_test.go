@@ -0,0 +1,165 @@
+package values
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_Interpolate(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "org-acme",
+			Labels: map[string]string{
+				label.Cluster:      "abc12",
+				label.Organization: "acme",
+			},
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "monitoring",
+			Version:   "v1.2.3",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "org-acme",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		interpolate  bool
+		values       string
+		expectedData map[string]interface{}
+		errorMatcher func(error) bool
+	}{
+		{
+			name:        "case 0: interpolation disabled",
+			interpolate: false,
+			values:      "host: \"${{ .ClusterID }}.example.com\"\n",
+			expectedData: map[string]interface{}{
+				"host": "${{ .ClusterID }}.example.com",
+			},
+		},
+		{
+			name:        "case 1: placeholders are filled",
+			interpolate: true,
+			values: "host: \"${{ .ClusterID }}.example.com\"\n" +
+				"labels:\n- \"org=${{ .Organization }}\"\n" +
+				"app:\n  target: \"${{ .App.Namespace }}/${{ .App.Name }}@${{ .App.Version }}\"\n" +
+				"replicas: 2\n",
+			expectedData: map[string]interface{}{
+				"host":   "abc12.example.com",
+				"labels": []interface{}{"org=acme"},
+				"app": map[string]interface{}{
+					"target": "monitoring/test-app@1.2.3",
+				},
+				"replicas": float64(2),
+			},
+		},
+		{
+			name:         "case 2: unknown field",
+			interpolate:  true,
+			values:       "host: \"${{ .BaseDomain }}\"\n",
+			errorMatcher: IsInterpolationFailed,
+		},
+		{
+			name:         "case 3: invalid template",
+			interpolate:  true,
+			values:       "host: \"${{ .ClusterID \"\n",
+			errorMatcher: IsInterpolationFailed,
+		},
+		{
+			name:         "case 4: calling functions",
+			interpolate:  true,
+			values:       "host: \"${{ env \\\"HOME\\\" }}\"\n",
+			errorMatcher: IsInterpolationFailed,
+		},
+		{
+			name:         "case 5: calling built-in functions",
+			interpolate:  true,
+			values:       "host: \"${{ printf \\\"%0999999d\\\" 1 }}\"\n",
+			errorMatcher: IsInterpolationFailed,
+		},
+		{
+			name:         "case 6: pipelines",
+			interpolate:  true,
+			values:       "host: \"${{ .ClusterID | len }}\"\n",
+			errorMatcher: IsInterpolationFailed,
+		},
+		{
+			name:         "case 7: control structures",
+			interpolate:  true,
+			values:       "host: \"${{ range .ClusterID }}x${{ end }}\"\n",
+			errorMatcher: IsInterpolationFailed,
+		},
+		{
+			name:         "case 8: variables",
+			interpolate:  true,
+			values:       "host: \"${{ $id := .ClusterID }}${{ $id }}\"\n",
+			errorMatcher: IsInterpolationFailed,
+		},
+		{
+			name:         "case 9: template definitions",
+			interpolate:  true,
+			values:       "host: \"${{ define \\\"x\\\" }}x${{ end }}${{ .ClusterID }}\"\n",
+			errorMatcher: IsInterpolationFailed,
+		},
+		{
+			name:        "case 10: templates rendered by the chart are left untouched",
+			interpolate: true,
+			values: "fullname: \"{{ .Release.Name }}-x\"\n" +
+				"summary: \"{{ $labels.instance }} is down\"\n" +
+				"host: \"${{ .ClusterID }}-{{ .Release.Name }}\"\n",
+			expectedData: map[string]interface{}{
+				"fullname": "{{ .Release.Name }}-x",
+				"summary":  "{{ $labels.instance }} is down",
+				"host":     "abc12-{{ .Release.Name }}",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClient := clientgofake.NewClientset(
+				getConfigMapDefinition("test-cluster-values", "org-acme", map[string]string{
+					"values": tc.values,
+				}),
+			)
+
+			v, err := New(Config{
+				K8sClient:   k8sClient,
+				Logger:      microloggertest.New(),
+				Interpolate: tc.interpolate,
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			result, err := v.MergeAll(context.Background(), app, v1alpha1.Catalog{})
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expectedData) {
+				t.Fatalf("want matching data \n %s", cmp.Diff(result, tc.expectedData))
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// KindConfigMap is the kind of values read from a configmap. It matches
+	// the kind used by extra configs.
+	KindConfigMap = "configMap"
+	// KindSecret is the kind of values read from a secret. It matches the
+	// kind used by extra configs.
+	KindSecret = "secret"
+)
+
+// Layer is the position of a set of values in the merge order.
+type Layer string
+
+const (
+	LayerCatalog Layer = "catalog"
+	LayerApp     Layer = "app"
+	LayerUser    Layer = "user"
+	LayerExtra   Layer = "extra"
+	// LayerReference is the layer of values read through references, see
+	// Config.ResolveReferences.
+	LayerReference Layer = "reference"
+)
+
+// Origin identifies the object a set of values was read from.
+type Origin struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Priority  int    `json:"priority"`
+	Layer     Layer  `json:"layer"`
+}
+
+func (o Origin) String() string {
+	return fmt.Sprintf("%s %s %#q in namespace %#q", o.Layer, o.Kind, o.Name, o.Namespace)
+}
+
+// layer is a single set of values together with the object it was read from.
+type layer struct {
+	origin   Origin
+	data     map[string]interface{}
+	strategy MergeStrategy
+	// skipped is set when the layer is an optional extra config that does
+	// not exist. Skipped layers hold no values.
+	skipped bool
+}
+
+// extraConfigOrigins returns the origins of the given extra configs in the
+// order they were given.
+func extraConfigOrigins(extraConfigs []v1alpha1.AppExtraConfig) []Origin {
+	origins := make([]Origin, 0, len(extraConfigs))
+
+	for _, entry := range extraConfigs {
+		origins = append(origins, Origin{
+			Kind:      considerDefaultKind(entry.Kind),
+			Name:      entry.Name,
+			Namespace: entry.Namespace,
+			Priority:  considerDefaultPriority(entry.Priority),
+			Layer:     LayerExtra,
+		})
+	}
+
+	return origins
+}
+
+// fetchLayers fetches and parses the values of the given origins
+// concurrently, keeping their order. When fetching fails for several origins
+// the error of the first one in order is returned. Optional extra configs
+// that do not exist are returned as skipped layers. Layers are merged with
+// the configured merge strategy unless the app overrides it for an extra
+// config.
+func (v *Values) fetchLayers(ctx context.Context, app v1alpha1.App, origins []Origin) ([]layer, error) {
+	strategies, err := mergeStrategies(app)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	optional := v.optionalExtraConfigs(app)
+
+	layers := make([]layer, len(origins))
+	errs := make([]error, len(origins))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, v.maxConcurrentFetches)
+
+	for i, o := range origins {
+		semaphore <- struct{}{}
+
+		wg.Go(func() {
+			defer func() { <-semaphore }()
+
+			layers[i], errs[i] = v.fetchLayer(ctx, o)
+			if IsNotFound(errs[i]) && o.Layer == LayerExtra && optional[mergeStrategyKey(o)] {
+				v.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("skipped optional %s because it was not found", o))
+				layers[i], errs[i] = layer{origin: o, skipped: true}, nil
+			}
+		})
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	for i := range layers {
+		layers[i].strategy = v.mergeStrategy
+
+		s, ok := strategies[mergeStrategyKey(layers[i].origin)]
+		if ok && layers[i].origin.Layer == LayerExtra {
+			layers[i].strategy = s
+		}
+	}
+
+	return layers, nil
+}
+
+// fetchLayer fetches and parses the values of the given origin. Errors are
+// returned as SourceError.
+func (v *Values) fetchLayer(ctx context.Context, o Origin) (layer, error) {
+	data, err := v.fetchLayerData(ctx, o)
+	if err != nil {
+		return layer{}, microerror.Mask(newSourceError(o, err))
+	}
+
+	return layer{origin: o, data: data}, nil
+}
+
+func (v *Values) fetchLayerData(ctx context.Context, o Origin) (map[string]interface{}, error) {
+	source, ok := v.valueSources[o.Kind]
+	if !ok {
+		return nil, microerror.Maskf(unknownKindError, "no value source registered for kind %#q", o.Kind)
+	}
+
+	rawData, err := source.Get(ctx, o.Name, o.Namespace)
+	if apierrors.IsNotFound(err) {
+		return nil, microerror.Maskf(notFoundError, "%s %#q in namespace %#q not found", o.Kind, o.Name, o.Namespace)
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	err = v.checkSourceSize(o, rawData)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	data, err := v.extractValues(o, rawData)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	err = v.checkValuesShape(o, data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return data, nil
+}
+
+// mergeLayers merges the given layers in order into a new map. When
+// provenance is not nil the origin of every merged value is recorded in it.
+// It returns nil when there are no layers. Values deleted by `null` are kept
+// as markers until pruneDeleted is called.
+func (v *Values) mergeLayers(ctx context.Context, layers []layer, provenance *Provenance) (map[string]interface{}, error) {
+	if len(layers) == 0 {
+		// Return early as there is no config.
+		return nil, nil
+	}
+
+	result := map[string]interface{}{}
+
+	for _, l := range layers {
+		err := v.mergeLayer(ctx, result, l, provenance)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	return result, nil
+}
+
+// mergeLayer merges a single layer into the given destination, modifying it
+// inplace.
+func (v *Values) mergeLayer(ctx context.Context, destinationData map[string]interface{}, l layer, provenance *Provenance) error {
+	if l.skipped {
+		return nil
+	}
+
+	if provenance != nil {
+		provenance.mergeWithStrategy(newProvenance(l.origin, l.data), l.strategy)
+	}
+
+	mergeValues(destinationData, l.data, l.strategy)
+
+	v.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf(
+		"merged %#q in %#q of kind %#q and priority %d", l.origin.Name, l.origin.Namespace, l.origin.Kind, l.origin.Priority,
+	))
+
+	return nil
+}
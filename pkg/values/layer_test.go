@@ -0,0 +1,148 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+// slowValueSource returns `index: <name>` for every object after a short
+// delay and records how many calls were in flight at the same time.
+type slowValueSource struct {
+	mutex       sync.Mutex
+	inFlight    int
+	maxInFlight int
+
+	failing map[string]bool
+}
+
+func (s *slowValueSource) Get(ctx context.Context, name, namespace string) (map[string]string, error) {
+	s.mutex.Lock()
+	s.inFlight++
+	if s.inFlight > s.maxInFlight {
+		s.maxInFlight = s.inFlight
+	}
+	s.mutex.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	s.mutex.Lock()
+	s.inFlight--
+	s.mutex.Unlock()
+
+	if s.failing[name] {
+		return nil, microerror.Maskf(parsingError, "%s", name)
+	}
+
+	return map[string]string{"values": fmt.Sprintf("last: %s\n%s: true\n", name, name)}, nil
+}
+
+func (s *slowValueSource) IsSecret() bool {
+	return false
+}
+
+func Test_FetchLayersConcurrently(t *testing.T) {
+	var extraConfigs []v1alpha1.AppExtraConfig
+	expectedData := map[string]interface{}{}
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("extra-%d", i)
+		extraConfigs = append(extraConfigs, v1alpha1.AppExtraConfig{
+			Kind:      "slow",
+			Name:      name,
+			Namespace: "giantswarm",
+			// Reverse the merge order compared to the list order.
+			Priority: v1alpha1.ConfigPriorityMaximum - i,
+		})
+		expectedData[name] = true
+	}
+	expectedData["last"] = "extra-0"
+
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:      "test-catalog",
+			Name:         "test-app",
+			Namespace:    "giantswarm",
+			ExtraConfigs: extraConfigs,
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		maxConcurrentFetches int
+		failing              map[string]bool
+		expectedData         map[string]interface{}
+		expectedError        string
+	}{
+		{
+			name:                 "case 0: fetches are bounded and merged in priority order",
+			maxConcurrentFetches: 2,
+			expectedData:         expectedData,
+		},
+		{
+			name:                 "case 1: sequential fetches",
+			maxConcurrentFetches: 1,
+			expectedData:         expectedData,
+		},
+		{
+			name:                 "case 2: the error of the first layer in merge order is returned",
+			maxConcurrentFetches: 6,
+			failing: map[string]bool{
+				"extra-1": true,
+				"extra-4": true,
+			},
+			expectedError: "extra-4",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			source := &slowValueSource{failing: tc.failing}
+
+			v, err := New(Config{
+				K8sClient:            clientgofake.NewClientset(),
+				Logger:               microloggertest.New(),
+				MaxConcurrentFetches: tc.maxConcurrentFetches,
+				ValueSources: map[string]ValueSource{
+					"slow": source,
+				},
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			result, err := v.MergeAll(context.Background(), app, v1alpha1.Catalog{})
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("error == %#v, want %#q", err, tc.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expectedData) {
+				t.Fatalf("want matching data \n %s", cmp.Diff(result, tc.expectedData))
+			}
+
+			if source.maxInFlight > tc.maxConcurrentFetches {
+				t.Fatalf("%d fetches in flight, want at most %d", source.maxInFlight, tc.maxConcurrentFetches)
+			}
+		})
+	}
+}
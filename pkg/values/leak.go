@@ -0,0 +1,238 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// minHighEntropyLength is the minimum length of strings checked for
+	// high entropy. Shorter random strings are too common to be flagged.
+	minHighEntropyLength = 24
+	// minHighEntropy is the Shannon entropy in bits per character from which
+	// strings are considered random. Hex encoded hashes stay below it while
+	// base64 encoded tokens usually exceed it.
+	minHighEntropy = 4.2
+)
+
+var (
+	credentialKeyWords = map[string]bool{
+		"apikey":      true,
+		"credentials": true,
+		"passwd":      true,
+		"password":    true,
+		"token":       true,
+	}
+	// qualifiedCredentialKeyWords only name credentials when the word
+	// before them is one of credentialQualifierWords, e.g. `privateKey` and
+	// `clientSecret` but neither the `key` of tolerations nor
+	// `existingSecret`, which holds the name of a secret.
+	qualifiedCredentialKeyWords = map[string]bool{
+		"key":    true,
+		"secret": true,
+	}
+	credentialQualifierWords = map[string]bool{
+		"access":     true,
+		"api":        true,
+		"client":     true,
+		"encryption": true,
+		"private":    true,
+		"secret":     true,
+		"signing":    true,
+	}
+
+	pemPrivateKeyRegexp = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)
+	jwtRegexp           = regexp.MustCompile(`^eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`)
+	tokenRegexp         = regexp.MustCompile(`^[A-Za-z0-9+/=_-]+$`)
+)
+
+// Warning is a problem found while merging values that does not stop the
+// values from being merged.
+type Warning struct {
+	// Origin is the object the problem was found in.
+	Origin Origin `json:"origin"`
+	// Path is the path of the value the problem was found at, if any.
+	Path    []string `json:"path,omitempty"`
+	Message string   `json:"message"`
+}
+
+func (w Warning) String() string {
+	if len(w.Path) == 0 {
+		return fmt.Sprintf("%s: %s", w.Origin, w.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", w.Origin, formatPath(w.Path), w.Message)
+}
+
+// MergeConfigMapDataWithWarnings works like MergeConfigMapData but also
+// returns warnings. They include the optional extra configs that were skipped
+// because they do not exist and, with Config.DetectSecretLeaks set, values
+// looking like credentials.
+func (v *Values) MergeConfigMapDataWithWarnings(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (map[string]interface{}, []Warning, error) {
+	var warnings []Warning
+
+	data, err := v.mergeConfigMapData(ctx, app, catalog, nil, &warnings)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	return data, warnings, nil
+}
+
+// scanForSecretLeaks returns a warning for every value of the given layers not
+// read from a secret that looks like a credential. Nothing is detected unless
+// Config.DetectSecretLeaks is set. Warnings are logged too.
+func (v *Values) scanForSecretLeaks(ctx context.Context, layers []layer) []Warning {
+	if !v.detectSecretLeaks {
+		return nil
+	}
+
+	var warnings []Warning
+
+	for _, l := range layers {
+		if v.isSecretKind(l.origin.Kind) {
+			continue
+		}
+
+		for _, path := range leakedSecretPaths(nil, l.data) {
+			w := Warning{
+				Origin:  l.origin,
+				Path:    path.path,
+				Message: fmt.Sprintf("value looks like a credential (%s), move it to a secret", path.reason),
+			}
+
+			v.logger.LogCtx(ctx, "level", "warning", "message", w.String())
+
+			warnings = append(warnings, w)
+		}
+	}
+
+	return warnings
+}
+
+type leakedSecretPath struct {
+	path   []string
+	reason string
+}
+
+// leakedSecretPaths returns the paths of all values looking like
+// credentials, ordered by path.
+func leakedSecretPaths(path []string, value interface{}) []leakedSecretPath {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var paths []leakedSecretPath
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			paths = append(paths, leakedSecretPaths(appendPath(path, k), v[k])...)
+		}
+		return paths
+	case []interface{}:
+		var paths []leakedSecretPath
+		for i, e := range v {
+			paths = append(paths, leakedSecretPaths(appendPath(path, strconv.Itoa(i)), e)...)
+		}
+		return paths
+	case string:
+		reason := credentialReason(path, v)
+		if reason == "" {
+			return nil
+		}
+		return []leakedSecretPath{{path: path, reason: reason}}
+	default:
+		return nil
+	}
+}
+
+// credentialReason returns why the string at the given path looks like a
+// credential or an empty string when it does not.
+func credentialReason(path []string, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	switch {
+	case pemPrivateKeyRegexp.MatchString(value):
+		return "PEM private key"
+	case jwtRegexp.MatchString(value):
+		return "JWT"
+	case len(path) > 0 && isCredentialKey(path[len(path)-1]):
+		return fmt.Sprintf("key %#q", path[len(path)-1])
+	case len(value) >= minHighEntropyLength && tokenRegexp.MatchString(value) && shannonEntropy(value) >= minHighEntropy:
+		return "high entropy string"
+	}
+
+	return ""
+}
+
+// isCredentialKey returns true when the last word of the given key, e.g.
+// `token` of `accessToken` or `password` of `admin_password`, names a
+// credential. Keys starting with `existing`, like `existingSecret`, hold the
+// name of an object holding the credential and are never credentials.
+func isCredentialKey(k string) bool {
+	words := splitWords(k)
+	if len(words) == 0 || words[0] == "existing" {
+		return false
+	}
+
+	last := words[len(words)-1]
+	if qualifiedCredentialKeyWords[last] {
+		return len(words) > 1 && credentialQualifierWords[words[len(words)-2]]
+	}
+
+	return credentialKeyWords[last]
+}
+
+// splitWords splits camel case, snake case, kebab case and dotted keys into
+// lower case words.
+func splitWords(k string) []string {
+	var words []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+
+	for _, r := range k {
+		switch {
+		case r == '_' || r == '-' || r == '.':
+			flush()
+		case unicode.IsUpper(r) && len(current) > 0 && !unicode.IsUpper(current[len(current)-1]):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	return words
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+
+	var entropy float64
+	n := float64(len([]rune(s)))
+	for _, c := range counts {
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
@@ -0,0 +1,92 @@
+package values
+
+import (
+	"encoding/json"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+// checkSourceSize fails with limitExceededError when the data read for the
+// given origin is larger than Config.MaxSourceBytes. It is checked before
+// the data is parsed.
+func (v *Values) checkSourceSize(o Origin, data map[string]string) error {
+	if v.maxSourceBytes == 0 {
+		return nil
+	}
+
+	var size int
+	for k, value := range data {
+		size += len(k) + len(value)
+	}
+
+	if size > v.maxSourceBytes {
+		return microerror.Maskf(limitExceededError, "%s has %d bytes, more than the maximum of %d", o, size, v.maxSourceBytes)
+	}
+
+	return nil
+}
+
+// checkValuesShape fails with limitExceededError when the values parsed for
+// the given origin are nested deeper than Config.MaxDepth or have more keys
+// than Config.MaxKeys.
+func (v *Values) checkValuesShape(o Origin, data map[string]interface{}) error {
+	if v.maxDepth == 0 && v.maxKeys == 0 {
+		return nil
+	}
+
+	depth, keys := valuesShape(data)
+
+	if v.maxDepth > 0 && depth > v.maxDepth {
+		return microerror.Maskf(limitExceededError, "%s has values nested %d levels deep, more than the maximum of %d", o, depth, v.maxDepth)
+	}
+	if v.maxKeys > 0 && keys > v.maxKeys {
+		return microerror.Maskf(limitExceededError, "%s has %d keys, more than the maximum of %d", o, keys, v.maxKeys)
+	}
+
+	return nil
+}
+
+// checkTotalSize fails with limitExceededError when the merged values of the
+// given app are larger than Config.MaxTotalBytes when encoded as JSON.
+func (v *Values) checkTotalSize(app v1alpha1.App, data map[string]interface{}) error {
+	if v.maxTotalBytes == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(raw) > v.maxTotalBytes {
+		return microerror.Maskf(limitExceededError, "merged values of app %#q in namespace %#q have %d bytes, more than the maximum of %d", app.Name, app.Namespace, len(raw), v.maxTotalBytes)
+	}
+
+	return nil
+}
+
+// valuesShape returns how deep the given values are nested, counting maps
+// and lists, and how many keys all their maps have.
+func valuesShape(value interface{}) (depth int, keys int) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		var maxDepth int
+		for _, child := range typed {
+			d, k := valuesShape(child)
+			maxDepth = max(maxDepth, d)
+			keys += k
+		}
+		return maxDepth + 1, keys + len(typed)
+	case []interface{}:
+		var maxDepth int
+		for _, child := range typed {
+			d, k := valuesShape(child)
+			maxDepth = max(maxDepth, d)
+			keys += k
+		}
+		return maxDepth + 1, keys
+	default:
+		return 0, 0
+	}
+}
@@ -0,0 +1,106 @@
+package values
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_Limits(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+				},
+			},
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				Secret: v1alpha1.AppSpecUserConfigSecret{
+					Name:      "test-user-secrets",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	k8sClient := clientgofake.NewClientset(
+		getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{
+			"values": "a:\n  b:\n    c: 1\nd: 2\n",
+		}),
+		getSecretDefinition("test-user-secrets", "giantswarm", map[string][]byte{
+			"values": []byte("e: " + strings.Repeat("x", 100) + "\n"),
+		}),
+	)
+
+	tests := []struct {
+		name          string
+		config        Config
+		expectedError string
+	}{
+		{
+			name:   "case 0: within limits",
+			config: Config{MaxDepth: 3, MaxKeys: 4, MaxSourceBytes: 200, MaxTotalBytes: 200},
+		},
+		{
+			name:          "case 1: source too large",
+			config:        Config{MaxSourceBytes: 100},
+			expectedError: "user secret `test-user-secrets` in namespace `giantswarm` has 110 bytes",
+		},
+		{
+			name:          "case 2: values nested too deep",
+			config:        Config{MaxDepth: 2},
+			expectedError: "app configMap `test-cluster-values` in namespace `giantswarm` has values nested 3 levels deep",
+		},
+		{
+			name:          "case 3: too many keys",
+			config:        Config{MaxKeys: 3},
+			expectedError: "app configMap `test-cluster-values` in namespace `giantswarm` has 4 keys",
+		},
+		{
+			name:          "case 4: merged values too large",
+			config:        Config{MaxTotalBytes: 100},
+			expectedError: "merged values of app `my-test-app` in namespace `giantswarm`",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config := tc.config
+			config.K8sClient = k8sClient
+			config.Logger = microloggertest.New()
+
+			v, err := New(config)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			_, err = v.MergeAll(context.Background(), app, v1alpha1.Catalog{})
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("error == %#v, want nil", err)
+				}
+				return
+			}
+
+			if !IsLimitExceeded(err) {
+				t.Fatalf("error == %#v, want limit exceeded", err)
+			}
+			if !strings.Contains(err.Error(), tc.expectedError) {
+				t.Fatalf("error == %#q, want it to contain %#q", err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
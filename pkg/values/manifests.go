@@ -0,0 +1,243 @@
+package values
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/app/v8/pkg/key"
+)
+
+var (
+	configMapTypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}
+	secretTypeMeta    = metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}
+)
+
+// Manifests holds the objects needed to resolve values without a cluster.
+// Set it as Config.Manifests to read configmaps and secrets from it instead
+// of the Kubernetes API.
+type Manifests struct {
+	Apps       []v1alpha1.App
+	Catalogs   []v1alpha1.Catalog
+	ConfigMaps []corev1.ConfigMap
+	Secrets    []corev1.Secret
+}
+
+// ReadManifests reads App, Catalog, ConfigMap and Secret objects from a YAML
+// stream with one or more documents. Documents of other kinds are skipped.
+func ReadManifests(r io.Reader) (*Manifests, error) {
+	m := &Manifests{}
+
+	err := m.read(r)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return m, nil
+}
+
+// ReadManifestsDir reads App, Catalog, ConfigMap and Secret objects from all
+// `.yaml`, `.yml` and `.json` files in the given directory and its
+// subdirectories.
+func ReadManifestsDir(dir string) (*Manifests, error) {
+	m := &Manifests{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		err = m.read(bytes.NewReader(content))
+		if err != nil {
+			return microerror.Maskf(parsingError, "failed to read manifests from %#q, logs: %s", path, err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return m, nil
+}
+
+func (m *Manifests) read(r io.Reader) error {
+	reader := apiyaml.NewYAMLReader(bufio.NewReader(r))
+
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+
+		//  Skip over empty documents, i.e. a leading `---`
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		var typeMeta metav1.TypeMeta
+		err = yaml.Unmarshal(doc, &typeMeta)
+		if err != nil {
+			return microerror.Maskf(parsingError, "failed to parse manifest, logs: %s", err.Error())
+		}
+
+		switch typeMeta {
+		case v1alpha1.NewAppTypeMeta():
+			var app v1alpha1.App
+			err = yaml.Unmarshal(doc, &app)
+			if err != nil {
+				return microerror.Maskf(parsingError, "failed to parse app, logs: %s", err.Error())
+			}
+
+			m.Apps = append(m.Apps, app)
+		case v1alpha1.NewCatalogTypeMeta():
+			var catalog v1alpha1.Catalog
+			err = yaml.Unmarshal(doc, &catalog)
+			if err != nil {
+				return microerror.Maskf(parsingError, "failed to parse catalog, logs: %s", err.Error())
+			}
+
+			m.Catalogs = append(m.Catalogs, catalog)
+		case configMapTypeMeta:
+			var configMap corev1.ConfigMap
+			err = yaml.Unmarshal(doc, &configMap)
+			if err != nil {
+				return microerror.Maskf(parsingError, "failed to parse configmap, logs: %s", err.Error())
+			}
+
+			m.ConfigMaps = append(m.ConfigMaps, configMap)
+		case secretTypeMeta:
+			var s corev1.Secret
+			err = yaml.Unmarshal(doc, &s)
+			if err != nil {
+				return microerror.Maskf(parsingError, "failed to parse secret, logs: %s", err.Error())
+			}
+
+			// The API server merges `stringData` into `data` on write, so we
+			// do the same for secrets read from files.
+			for k, v := range s.StringData {
+				if s.Data == nil {
+					s.Data = map[string][]byte{}
+				}
+				s.Data[k] = []byte(v)
+			}
+
+			m.Secrets = append(m.Secrets, s)
+		}
+	}
+
+	return nil
+}
+
+// App returns the app with the given namespace and name.
+func (m *Manifests) App(namespace, name string) (v1alpha1.App, error) {
+	for _, app := range m.Apps {
+		if app.Namespace == namespace && app.Name == name {
+			return app, nil
+		}
+	}
+
+	return v1alpha1.App{}, microerror.Maskf(notFoundError, "app %#q in namespace %#q not found", name, namespace)
+}
+
+// Catalog returns the catalog the given app is installed from. Like the
+// app platform it looks for the catalog in `.spec.catalogNamespace` or, when
+// that is not set, in the `default` and `giantswarm` namespaces.
+func (m *Manifests) Catalog(app v1alpha1.App) (v1alpha1.Catalog, error) {
+	if key.CatalogName(app) == "" {
+		return v1alpha1.Catalog{}, nil
+	}
+
+	var namespaces []string
+	{
+		if key.CatalogNamespace(app) != "" {
+			namespaces = []string{key.CatalogNamespace(app)}
+		} else {
+			namespaces = []string{metav1.NamespaceDefault, "giantswarm"}
+		}
+	}
+
+	for _, ns := range namespaces {
+		for _, catalog := range m.Catalogs {
+			if catalog.Namespace == ns && catalog.Name == key.CatalogName(app) {
+				return catalog, nil
+			}
+		}
+	}
+
+	return v1alpha1.Catalog{}, microerror.Maskf(notFoundError, "catalog %#q not found", key.CatalogName(app))
+}
+
+// manifestSource is the ValueSource reading values from configmaps or
+// secrets in manifests.
+type manifestSource struct {
+	logger    micrologger.Logger
+	manifests *Manifests
+
+	isSecret bool
+}
+
+func (s *manifestSource) IsSecret() bool {
+	return s.isSecret
+}
+
+func (s *manifestSource) Get(ctx context.Context, name, namespace string) (map[string]string, error) {
+	if name == "" {
+		// Return early as no object has been specified.
+		return nil, nil
+	}
+
+	kind := configmap
+	if s.isSecret {
+		kind = secret
+	}
+
+	s.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("looking for %s %#q in namespace %#q in manifests", kind, name, namespace))
+
+	if s.isSecret {
+		for _, o := range s.manifests.Secrets {
+			if o.Namespace == namespace && o.Name == name {
+				return toStringMap(o.Data), nil
+			}
+		}
+	} else {
+		for _, o := range s.manifests.ConfigMaps {
+			if o.Namespace == namespace && o.Name == name {
+				return o.Data, nil
+			}
+		}
+	}
+
+	return nil, microerror.Maskf(notFoundError, "%s %#q in namespace %#q not found", kind, name, namespace)
+}
@@ -0,0 +1,212 @@
+package values
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+)
+
+const testManifests = `---
+apiVersion: application.giantswarm.io/v1alpha1
+kind: App
+metadata:
+  name: my-test-app
+  namespace: org-acme
+spec:
+  catalog: test-catalog
+  name: test-app
+  namespace: test
+  version: 1.0.0
+  config:
+    configMap:
+      name: test-cluster-values
+      namespace: org-acme
+  userConfig:
+    configMap:
+      name: test-user-values
+      namespace: org-acme
+    secret:
+      name: test-user-secrets
+      namespace: org-acme
+---
+apiVersion: application.giantswarm.io/v1alpha1
+kind: Catalog
+metadata:
+  name: test-catalog
+  namespace: giantswarm
+spec:
+  title: test-catalog
+  config:
+    configMap:
+      name: test-catalog-values
+      namespace: giantswarm
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-catalog-values
+  namespace: giantswarm
+data:
+  values: |
+    catalog: test
+    test: catalog
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-cluster-values
+  namespace: org-acme
+data:
+  values: |
+    cluster: test
+    test: app
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-user-values
+  namespace: org-acme
+data:
+  values: |
+    test: user
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: test-user-secrets
+  namespace: org-acme
+stringData:
+  values: |
+    secret: test
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ignored
+  namespace: org-acme
+`
+
+func Test_ManifestsMergeAll(t *testing.T) {
+	dir := t.TempDir()
+
+	// Split the manifests over two files and a nested directory to cover
+	// reading directories.
+	docs := strings.SplitN(testManifests, "---\napiVersion: v1\nkind: ConfigMap", 2)
+	err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte(docs[0]), 0600)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	err = os.MkdirAll(filepath.Join(dir, "config"), 0700)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "config", "values.yml"), []byte("---\napiVersion: v1\nkind: ConfigMap"+docs[1]), 0600)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "README.md"), []byte("# not a manifest"), 0600)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	fromStream, err := ReadManifests(strings.NewReader(testManifests))
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	fromDir, err := ReadManifestsDir(dir)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	expectedData := map[string]interface{}{
+		"catalog": "test",
+		"cluster": "test",
+		"secret":  "test",
+		"test":    "user",
+	}
+
+	for name, manifests := range map[string]*Manifests{"stream": fromStream, "dir": fromDir} {
+		t.Run(name, func(t *testing.T) {
+			if len(manifests.Apps) != 1 || len(manifests.Catalogs) != 1 || len(manifests.ConfigMaps) != 3 || len(manifests.Secrets) != 1 {
+				t.Fatalf("want 1 app, 1 catalog, 3 configmaps and 1 secret, got %d, %d, %d and %d",
+					len(manifests.Apps), len(manifests.Catalogs), len(manifests.ConfigMaps), len(manifests.Secrets))
+			}
+
+			v, err := New(Config{
+				Logger:    microloggertest.New(),
+				Manifests: manifests,
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			app, err := manifests.App("org-acme", "my-test-app")
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+			catalog, err := manifests.Catalog(app)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			result, err := v.MergeAll(context.Background(), app, catalog)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			if !reflect.DeepEqual(result, expectedData) {
+				t.Fatalf("want matching data \n %s", cmp.Diff(result, expectedData))
+			}
+		})
+	}
+}
+
+func Test_ManifestsNotFound(t *testing.T) {
+	manifests, err := ReadManifests(strings.NewReader(testManifests))
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	_, err = manifests.App("org-acme", "missing")
+	if !IsNotFound(err) {
+		t.Fatalf("error == %#v, want not found", err)
+	}
+
+	app, err := manifests.App("org-acme", "my-test-app")
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	app.Spec.CatalogNamespace = "default"
+
+	_, err = manifests.Catalog(app)
+	if !IsNotFound(err) {
+		t.Fatalf("error == %#v, want not found", err)
+	}
+
+	manifests.ConfigMaps = manifests.ConfigMaps[1:]
+
+	v, err := New(Config{
+		Logger:    microloggertest.New(),
+		Manifests: manifests,
+	})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	app.Spec.CatalogNamespace = ""
+	catalog, err := manifests.Catalog(app)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	_, err = v.MergeAll(context.Background(), app, catalog)
+	if !IsNotFound(err) {
+		t.Fatalf("error == %#v, want not found", err)
+	}
+}
@@ -0,0 +1,193 @@
+package values
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/yaml"
+)
+
+// MergeStrategiesAnnotation is the App annotation overriding the merge
+// strategy of single extra configs. Its value is a YAML map from
+// `<kind>/<namespace>/<name>` of an extra config to its MergeStrategy, e.g.
+//
+//	configMap/giantswarm/cluster-values:
+//	  deleteNulls: true
+//	  lists: mergeByKey
+//	  listMergeKey: name
+const MergeStrategiesAnnotation = "values.giantswarm.io/merge-strategies"
+
+const (
+	// ListMergeReplace replaces lists of lower layers. This is the default.
+	ListMergeReplace = "replace"
+	// ListMergeAppend appends the entries of a list to the list of lower
+	// layers.
+	ListMergeAppend = "append"
+	// ListMergeByKey merges entries of lists being maps with the same value
+	// under MergeStrategy.ListMergeKey, e.g. `env` entries by `name`. Other
+	// entries are appended.
+	ListMergeByKey = "mergeByKey"
+)
+
+// MergeStrategy defines how the values of a layer are merged on top of the
+// values of lower layers. Maps are always merged recursively and other values
+// replace the values of lower layers.
+type MergeStrategy struct {
+	// DeleteNulls makes `null` values delete the value of lower layers
+	// instead of setting it to `null`, like Helm does with chart defaults.
+	DeleteNulls bool `json:"deleteNulls,omitempty"`
+	// Lists is how lists are merged. It is one of the ListMerge* constants
+	// and defaults to ListMergeReplace.
+	Lists string `json:"lists,omitempty"`
+	// ListMergeKey is the key list entries are matched by when Lists is
+	// ListMergeByKey.
+	ListMergeKey string `json:"listMergeKey,omitempty"`
+}
+
+func (s MergeStrategy) validate() error {
+	switch s.Lists {
+	case "", ListMergeReplace, ListMergeAppend:
+	case ListMergeByKey:
+		if s.ListMergeKey == "" {
+			return microerror.Maskf(invalidConfigError, "list merge key must not be empty when lists are merged by key")
+		}
+	default:
+		return microerror.Maskf(invalidConfigError, "lists must be merged by one of %#q, %#q or %#q but got %#q", ListMergeReplace, ListMergeAppend, ListMergeByKey, s.Lists)
+	}
+
+	return nil
+}
+
+// deleted marks a value deleted by a `null` of a higher layer. Deleted values
+// are only removed once all layers are merged, so that `null` in a secret
+// also deletes a configmap value.
+type deleted struct{}
+
+// mergeStrategies returns the merge strategy of every extra config listed in
+// the MergeStrategiesAnnotation of the given app by `<kind>/<namespace>/<name>`.
+func mergeStrategies(app v1alpha1.App) (map[string]MergeStrategy, error) {
+	value, ok := app.GetAnnotations()[MergeStrategiesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var strategies map[string]MergeStrategy
+	err := yaml.UnmarshalStrict([]byte(value), &strategies)
+	if err != nil {
+		return nil, microerror.Maskf(parsingError, "failed to parse annotation %#q, logs: %s", MergeStrategiesAnnotation, err.Error())
+	}
+
+	for k, s := range strategies {
+		err = s.validate()
+		if err != nil {
+			return nil, microerror.Maskf(parsingError, "invalid merge strategy for %#q in annotation %#q, logs: %s", k, MergeStrategiesAnnotation, err.Error())
+		}
+	}
+
+	return strategies, nil
+}
+
+func mergeStrategyKey(o Origin) string {
+	return fmt.Sprintf("%s/%s/%s", o.Kind, o.Namespace, o.Name)
+}
+
+// mergeValues merges src into dst, modifying dst inplace. Values of src are
+// copied so that dst shares no maps or lists with it.
+func mergeValues(dst, src map[string]interface{}, s MergeStrategy) {
+	for k, srcValue := range src {
+		if srcValue == nil && s.DeleteNulls {
+			dst[k] = deleted{}
+			continue
+		}
+
+		switch srcTyped := srcValue.(type) {
+		case map[string]interface{}:
+			dstMap, ok := dst[k].(map[string]interface{})
+			if ok {
+				mergeValues(dstMap, srcTyped, s)
+				continue
+			}
+		case []interface{}:
+			dstList, ok := dst[k].([]interface{})
+			if ok {
+				dst[k] = mergeLists(dstList, srcTyped, s)
+				continue
+			}
+		}
+
+		dst[k] = deepCopyValue(srcValue)
+	}
+}
+
+func mergeLists(dst, src []interface{}, s MergeStrategy) []interface{} {
+	switch s.Lists {
+	case ListMergeAppend:
+		return append(dst, deepCopyValue(src).([]interface{})...)
+	case ListMergeByKey:
+		for _, srcEntry := range src {
+			i := indexByKey(dst, srcEntry, s.ListMergeKey)
+			if i < 0 {
+				dst = append(dst, deepCopyValue(srcEntry))
+				continue
+			}
+
+			mergeValues(dst[i].(map[string]interface{}), srcEntry.(map[string]interface{}), s)
+		}
+
+		return dst
+	default:
+		return deepCopyValue(src).([]interface{})
+	}
+}
+
+// indexByKey returns the index of the map in list having the same value
+// under the given key as entry or -1 if there is none.
+func indexByKey(list []interface{}, entry interface{}, k string) int {
+	entryMap, ok := entry.(map[string]interface{})
+	if !ok {
+		return -1
+	}
+	value, ok := entryMap[k]
+	if !ok {
+		return -1
+	}
+
+	for i, e := range list {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		v, ok := m[k]
+		if ok && reflect.DeepEqual(v, value) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// pruneDeleted removes values deleted by `null` of higher layers from data,
+// modifying it inplace.
+func pruneDeleted(data map[string]interface{}) {
+	for k, v := range data {
+		if _, ok := v.(deleted); ok {
+			delete(data, k)
+			continue
+		}
+
+		pruneDeletedValue(v)
+	}
+}
+
+func pruneDeletedValue(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		pruneDeleted(v)
+	case []interface{}:
+		for _, e := range v {
+			pruneDeletedValue(e)
+		}
+	}
+}
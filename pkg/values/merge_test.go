@@ -0,0 +1,281 @@
+package values
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_MergeValues(t *testing.T) {
+	dst := map[string]interface{}{
+		"replicas": float64(1),
+		"ingress": map[string]interface{}{
+			"enabled": true,
+			"host":    "a",
+		},
+		"env": []interface{}{
+			map[string]interface{}{"name": "A", "value": "1"},
+			map[string]interface{}{"name": "B", "value": "2"},
+		},
+	}
+	src := map[string]interface{}{
+		"ingress": map[string]interface{}{
+			"host": nil,
+		},
+		"env": []interface{}{
+			map[string]interface{}{"name": "B", "value": "3"},
+			map[string]interface{}{"name": "C", "value": "4"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		strategy     MergeStrategy
+		expectedData map[string]interface{}
+	}{
+		{
+			name:     "case 0: default strategy",
+			strategy: MergeStrategy{},
+			expectedData: map[string]interface{}{
+				"replicas": float64(1),
+				"ingress": map[string]interface{}{
+					"enabled": true,
+					"host":    nil,
+				},
+				"env": []interface{}{
+					map[string]interface{}{"name": "B", "value": "3"},
+					map[string]interface{}{"name": "C", "value": "4"},
+				},
+			},
+		},
+		{
+			name: "case 1: delete nulls and append lists",
+			strategy: MergeStrategy{
+				DeleteNulls: true,
+				Lists:       ListMergeAppend,
+			},
+			expectedData: map[string]interface{}{
+				"replicas": float64(1),
+				"ingress": map[string]interface{}{
+					"enabled": true,
+				},
+				"env": []interface{}{
+					map[string]interface{}{"name": "A", "value": "1"},
+					map[string]interface{}{"name": "B", "value": "2"},
+					map[string]interface{}{"name": "B", "value": "3"},
+					map[string]interface{}{"name": "C", "value": "4"},
+				},
+			},
+		},
+		{
+			name: "case 2: merge lists by key",
+			strategy: MergeStrategy{
+				Lists:        ListMergeByKey,
+				ListMergeKey: "name",
+			},
+			expectedData: map[string]interface{}{
+				"replicas": float64(1),
+				"ingress": map[string]interface{}{
+					"enabled": true,
+					"host":    nil,
+				},
+				"env": []interface{}{
+					map[string]interface{}{"name": "A", "value": "1"},
+					map[string]interface{}{"name": "B", "value": "3"},
+					map[string]interface{}{"name": "C", "value": "4"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := deepCopyValues(dst)
+
+			mergeValues(result, src, tc.strategy)
+			pruneDeleted(result)
+
+			if !reflect.DeepEqual(result, tc.expectedData) {
+				t.Fatalf("want matching data \n %s", cmp.Diff(result, tc.expectedData))
+			}
+		})
+	}
+}
+
+func Test_MergeStrategies(t *testing.T) {
+	k8sClient := clientgofake.NewClientset(
+		getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{
+			"values": "env:\n- name: A\n  value: \"1\"\nreplicas: 1\nsecret: plain\n",
+		}),
+		getConfigMapDefinition("test-extra-values", "giantswarm", map[string]string{
+			"values": "env:\n- name: A\n  value: \"2\"\n- name: B\n  value: \"3\"\n",
+		}),
+		getSecretDefinition("test-user-secrets", "giantswarm", map[string][]byte{
+			"values": []byte("secret: null\n"),
+		}),
+	)
+
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+			Annotations: map[string]string{
+				MergeStrategiesAnnotation: "configMap/giantswarm/test-extra-values:\n  lists: mergeByKey\n  listMergeKey: name\n",
+			},
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+				},
+			},
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				Secret: v1alpha1.AppSpecUserConfigSecret{
+					Name:      "test-user-secrets",
+					Namespace: "giantswarm",
+				},
+			},
+			ExtraConfigs: []v1alpha1.AppExtraConfig{
+				{
+					Kind:      "configMap",
+					Name:      "test-extra-values",
+					Namespace: "giantswarm",
+					Priority:  v1alpha1.ConfigPriorityMaximum,
+				},
+			},
+		},
+	}
+
+	v, err := New(Config{
+		K8sClient: k8sClient,
+		Logger:    microloggertest.New(),
+		MergeStrategy: MergeStrategy{
+			DeleteNulls: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	result, err := v.MergeAll(context.Background(), app, v1alpha1.Catalog{})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	// The null in the secret deletes the configmap value and the env entries
+	// of the extra config are merged by name.
+	expectedData := map[string]interface{}{
+		"env": []interface{}{
+			map[string]interface{}{"name": "A", "value": "2"},
+			map[string]interface{}{"name": "B", "value": "3"},
+		},
+		"replicas": float64(1),
+	}
+	if !reflect.DeepEqual(result, expectedData) {
+		t.Fatalf("want matching data \n %s", cmp.Diff(result, expectedData))
+	}
+
+	app.Annotations[MergeStrategiesAnnotation] = "configMap/giantswarm/test-extra-values:\n  lists: mergeByKey\n"
+
+	_, err = v.MergeAll(context.Background(), app, v1alpha1.Catalog{})
+	if !IsParsingError(err) {
+		t.Fatalf("error == %#v, want parsing error", err)
+	}
+
+	_, err = New(Config{
+		K8sClient: k8sClient,
+		Logger:    microloggertest.New(),
+		MergeStrategy: MergeStrategy{
+			Lists: "zip",
+		},
+	})
+	if !IsInvalidConfig(err) {
+		t.Fatalf("error == %#v, want invalid config", err)
+	}
+}
+
+func Test_MergeStrategiesSecretExtraConfig(t *testing.T) {
+	k8sClient := clientgofake.NewClientset(
+		getConfigMapDefinition("test-user-values", "giantswarm", map[string]string{
+			"values": "env:\n- A\n",
+		}),
+		getSecretDefinition("test-extra-secrets", "giantswarm", map[string][]byte{
+			"values": []byte("env:\n- B\n"),
+		}),
+	)
+
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+			Annotations: map[string]string{
+				MergeStrategiesAnnotation: "secret/giantswarm/test-extra-secrets:\n  lists: append\n",
+			},
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+					Name:      "test-user-values",
+					Namespace: "giantswarm",
+				},
+			},
+			ExtraConfigs: []v1alpha1.AppExtraConfig{
+				{
+					Kind:      "secret",
+					Name:      "test-extra-secrets",
+					Namespace: "giantswarm",
+					Priority:  v1alpha1.ConfigPriorityMaximum,
+				},
+			},
+		},
+	}
+
+	v, err := New(Config{
+		K8sClient: k8sClient,
+		Logger:    microloggertest.New(),
+	})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	result, provenance, err := v.MergeAllWithProvenance(context.Background(), app, v1alpha1.Catalog{})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	// The list of the secret extra config is appended to the list of the
+	// user configmap as its strategy defines.
+	expectedData := map[string]interface{}{
+		"env": []interface{}{"A", "B"},
+	}
+	if !reflect.DeepEqual(result, expectedData) {
+		t.Fatalf("want matching data \n %s", cmp.Diff(result, expectedData))
+	}
+
+	leaf := provenance.Lookup("env")
+	if leaf == nil {
+		t.Fatalf("provenance of `env` not found")
+	}
+	if leaf.Origin.Name != "test-extra-secrets" {
+		t.Fatalf("origin == %#q, want %#q", leaf.Origin.Name, "test-extra-secrets")
+	}
+	if len(leaf.Contributors) != 1 || leaf.Contributors[0].Name != "test-user-values" {
+		t.Fatalf("contributors == %#v, want the user configmap", leaf.Contributors)
+	}
+	if !reflect.DeepEqual(leaf.Value, expectedData["env"]) {
+		t.Fatalf("want matching value \n %s", cmp.Diff(leaf.Value, expectedData["env"]))
+	}
+}
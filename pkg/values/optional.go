@@ -0,0 +1,85 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+// OptionalExtraConfigsAnnotation is the App annotation marking single extra
+// configs as optional. Its value is a comma separated list of
+// `<kind>/<namespace>/<name>` of extra configs, e.g.
+//
+//	configMap/giantswarm/cluster-values,secret/giantswarm/cluster-secrets
+//
+// Optional extra configs that do not exist are skipped instead of failing
+// the merge.
+const OptionalExtraConfigsAnnotation = "values.giantswarm.io/optional-extra-configs"
+
+// MergeAllWithWarnings works like MergeAll but also returns warnings. They
+// include the optional extra configs that were skipped because they do not
+// exist.
+func (v *Values) MergeAllWithWarnings(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (map[string]interface{}, []Warning, error) {
+	var warnings []Warning
+
+	data, err := v.mergeAll(ctx, app, catalog, nil, nil, &warnings)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	return data, warnings, nil
+}
+
+// MergeSecretDataWithWarnings works like MergeSecretData but also returns
+// warnings. They include the optional extra configs that were skipped because
+// they do not exist.
+func (v *Values) MergeSecretDataWithWarnings(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (map[string]interface{}, []Warning, error) {
+	var warnings []Warning
+
+	data, err := v.mergeSecretData(ctx, app, catalog, nil, &warnings)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	return data, warnings, nil
+}
+
+// optionalExtraConfigs returns the keys of the extra configs of the given app
+// that are optional, either by Config.OptionalExtraConfigs or by the
+// OptionalExtraConfigsAnnotation.
+func (v *Values) optionalExtraConfigs(app v1alpha1.App) map[string]bool {
+	optional := map[string]bool{}
+
+	for _, k := range v.optionalExtraConfigKeys {
+		optional[k] = true
+	}
+
+	for _, k := range strings.Split(app.GetAnnotations()[OptionalExtraConfigsAnnotation], ",") {
+		k = strings.TrimSpace(k)
+		if k != "" {
+			optional[k] = true
+		}
+	}
+
+	return optional
+}
+
+// skippedLayers returns a warning for every layer skipped because its
+// optional extra config does not exist.
+func skippedLayers(layers []layer) []Warning {
+	var warnings []Warning
+
+	for _, l := range layers {
+		if l.skipped {
+			warnings = append(warnings, Warning{
+				Origin:  l.origin,
+				Message: fmt.Sprintf("optional %s not found, skipped", l.origin.Kind),
+			})
+		}
+	}
+
+	return warnings
+}
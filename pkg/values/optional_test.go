@@ -0,0 +1,135 @@
+package values
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_OptionalExtraConfigs(t *testing.T) {
+	extraConfigs := []v1alpha1.AppExtraConfig{
+		{
+			Kind:      "configMap",
+			Name:      "test-extra-values",
+			Namespace: "giantswarm",
+		},
+		{
+			Kind:      "configMap",
+			Name:      "test-missing-values",
+			Namespace: "giantswarm",
+			Priority:  120,
+		},
+		{
+			Kind:      "secret",
+			Name:      "test-missing-secrets",
+			Namespace: "giantswarm",
+		},
+	}
+
+	missingConfigMap := Origin{Kind: KindConfigMap, Name: "test-missing-values", Namespace: "giantswarm", Priority: 120, Layer: LayerExtra}
+	missingSecret := Origin{Kind: KindSecret, Name: "test-missing-secrets", Namespace: "giantswarm", Priority: 25, Layer: LayerExtra}
+
+	tests := []struct {
+		name                 string
+		annotations          map[string]string
+		optionalExtraConfigs []string
+		expectedData         map[string]interface{}
+		expectedWarnings     []Warning
+		errorMatcher         func(error) bool
+	}{
+		{
+			name:         "case 0: missing extra configs are required by default",
+			errorMatcher: IsNotFound,
+		},
+		{
+			name: "case 1: only some missing extra configs are optional",
+			annotations: map[string]string{
+				OptionalExtraConfigsAnnotation: "configMap/giantswarm/test-missing-values",
+			},
+			errorMatcher: IsNotFound,
+		},
+		{
+			name: "case 2: missing extra configs optional by annotation",
+			annotations: map[string]string{
+				OptionalExtraConfigsAnnotation: "configMap/giantswarm/test-missing-values, secret/giantswarm/test-missing-secrets",
+			},
+			expectedData: map[string]interface{}{
+				"test": "extra",
+			},
+			expectedWarnings: []Warning{
+				{Origin: missingConfigMap, Message: "optional configMap not found, skipped"},
+				{Origin: missingSecret, Message: "optional secret not found, skipped"},
+			},
+		},
+		{
+			name: "case 3: missing extra configs optional by config and annotation",
+			annotations: map[string]string{
+				OptionalExtraConfigsAnnotation: "secret/giantswarm/test-missing-secrets",
+			},
+			optionalExtraConfigs: []string{"configMap/giantswarm/test-missing-values"},
+			expectedData: map[string]interface{}{
+				"test": "extra",
+			},
+			expectedWarnings: []Warning{
+				{Origin: missingConfigMap, Message: "optional configMap not found, skipped"},
+				{Origin: missingSecret, Message: "optional secret not found, skipped"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-test-app",
+					Namespace:   "giantswarm",
+					Annotations: tc.annotations,
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:      "test-catalog",
+					Name:         "test-app",
+					Namespace:    "giantswarm",
+					ExtraConfigs: extraConfigs,
+				},
+			}
+
+			k8sClient := clientgofake.NewClientset(
+				getConfigMapDefinition("test-extra-values", "giantswarm", map[string]string{
+					"values": "test: extra\n",
+				}),
+			)
+
+			v, err := New(Config{
+				K8sClient:            k8sClient,
+				Logger:               microloggertest.New(),
+				OptionalExtraConfigs: tc.optionalExtraConfigs,
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			result, warnings, err := v.MergeAllWithWarnings(context.Background(), app, v1alpha1.Catalog{})
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expectedData) {
+				t.Fatalf("want matching data \n %s", cmp.Diff(result, tc.expectedData))
+			}
+			if !reflect.DeepEqual(warnings, tc.expectedWarnings) {
+				t.Fatalf("want matching warnings \n %s", cmp.Diff(warnings, tc.expectedWarnings))
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package values
+
+import (
+	"maps"
+	"slices"
+)
+
+// Provenance is a tree mirroring a set of merged values. For every leaf it
+// records the origin that set the value and every earlier value it
+// overrode. Maps are represented by nodes with children, every other value,
+// including lists and nulls, is a leaf. Values deleted by `null` have no
+// provenance.
+type Provenance struct {
+	// Children holds the provenance of the values nested in a map. It is nil
+	// for leaves.
+	Children map[string]*Provenance `json:"children,omitempty"`
+	// Origin is the object that set the value of a leaf.
+	Origin *Origin `json:"origin,omitempty"`
+	// Value is the merged value of a leaf.
+	Value interface{} `json:"value,omitempty"`
+	// Contributors are the origins of the earlier entries of a list that
+	// was appended to or merged by key, oldest first. Origin set the
+	// latest entries.
+	Contributors []Origin `json:"contributors,omitempty"`
+	// Overrides are the values that were replaced at this path, oldest
+	// first. When a whole map was replaced it holds the leaves of that map.
+	Overrides []Override `json:"overrides,omitempty"`
+}
+
+// Override is a value that was replaced by a later layer.
+type Override struct {
+	Origin Origin      `json:"origin"`
+	Value  interface{} `json:"value"`
+}
+
+// NewProvenance returns an empty provenance tree.
+func NewProvenance() *Provenance {
+	return &Provenance{
+		Children: map[string]*Provenance{},
+	}
+}
+
+// newProvenance returns the provenance tree of the given data when all of
+// it is read from the given origin.
+func newProvenance(o Origin, data map[string]interface{}) *Provenance {
+	p := NewProvenance()
+
+	for k, v := range data {
+		nested, ok := v.(map[string]interface{})
+		if ok {
+			p.Children[k] = newProvenance(o, nested)
+			continue
+		}
+
+		origin := o
+		p.Children[k] = &Provenance{
+			Origin: &origin,
+			Value:  v,
+		}
+	}
+
+	return p
+}
+
+// IsLeaf returns true when the node represents a value other than a map.
+func (p *Provenance) IsLeaf() bool {
+	return p.Children == nil
+}
+
+// Lookup returns the provenance of the value at the given path or nil if
+// there is no such value.
+func (p *Provenance) Lookup(path ...string) *Provenance {
+	current := p
+
+	for _, k := range path {
+		if current == nil || current.IsLeaf() {
+			return nil
+		}
+
+		current = current.Children[k]
+	}
+
+	return current
+}
+
+// originOf returns the origin of the value at the given path. Paths below a
+// leaf, e.g. into a list, resolve to the origin of the leaf. It returns nil
+// for maps and unknown paths.
+func (p *Provenance) originOf(path []string) *Origin {
+	leaf := p.leafOf(path)
+	if leaf == nil {
+		return nil
+	}
+
+	return leaf.Origin
+}
+
+// leafOf returns the leaf holding the value at the given path. Paths below a
+// leaf, e.g. into a list, resolve to the leaf. It returns nil for maps and
+// unknown paths.
+func (p *Provenance) leafOf(path []string) *Provenance {
+	current := p
+
+	for _, k := range path {
+		if current.IsLeaf() {
+			break
+		}
+
+		current = current.Children[k]
+		if current == nil {
+			return nil
+		}
+	}
+
+	if !current.IsLeaf() {
+		return nil
+	}
+
+	return current
+}
+
+// replace makes the value at the given path a leaf set by the given origin,
+// keeping what was set there before as overrides. When the path leads into a
+// leaf, e.g. into a list, that leaf keeps its origin and the given origin is
+// added to its contributors, as its value is now made of both.
+func (p *Provenance) replace(path []string, o Origin, value interface{}) {
+	parent := p
+
+	for i, k := range path {
+		child := parent.Children[k]
+		if child == nil {
+			return
+		}
+
+		if i < len(path)-1 && child.IsLeaf() {
+			parent.Children[k] = &Provenance{
+				Origin:       child.Origin,
+				Value:        child.Value,
+				Contributors: append(slices.Clone(child.Contributors), o),
+				Overrides:    child.Overrides,
+			}
+
+			return
+		}
+
+		if i == len(path)-1 {
+			origin := o
+			parent.Children[k] = &Provenance{
+				Origin:    &origin,
+				Value:     value,
+				Overrides: child.history(),
+			}
+
+			return
+		}
+
+		parent = child
+	}
+}
+
+// Walk calls fn for every leaf of the tree in lexical order of their paths.
+func (p *Provenance) Walk(fn func(path []string, leaf *Provenance)) {
+	p.walk(nil, fn)
+}
+
+func (p *Provenance) walk(path []string, fn func(path []string, leaf *Provenance)) {
+	if p.IsLeaf() {
+		fn(path, p)
+		return
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(p.Children)) {
+		p.Children[k].walk(appendPath(path, k), fn)
+	}
+}
+
+// origins returns the origins that contributed to the value of a leaf,
+// oldest first.
+func (p *Provenance) origins() []Origin {
+	origins := append([]Origin{}, p.Contributors...)
+	if p.Origin != nil {
+		origins = append(origins, *p.Origin)
+	}
+
+	return origins
+}
+
+// history returns the values set at this node so far, oldest first. Values
+// deleted by `null` are returned as `null`.
+func (p *Provenance) history() []Override {
+	history := append([]Override{}, p.Overrides...)
+
+	if p.IsLeaf() {
+		if p.Origin != nil {
+			value := p.Value
+			if _, ok := value.(deleted); ok {
+				value = nil
+			}
+			history = append(history, Override{Origin: *p.Origin, Value: value})
+		}
+		return history
+	}
+
+	p.Walk(func(_ []string, leaf *Provenance) {
+		history = append(history, leaf.history()...)
+	})
+
+	return history
+}
+
+// merge merges src into p following the rules used to merge the values
+// themselves with the default strategy: maps are merged recursively and any
+// other value replaces the existing one.
+func (p *Provenance) merge(src *Provenance) {
+	p.mergeWithStrategy(src, MergeStrategy{})
+}
+
+// mergeWithStrategy merges src into p following the rules mergeValues uses
+// to merge the values themselves with the given strategy: maps are merged
+// recursively, lists are merged as the strategy defines and any other value
+// replaces the existing one. Values deleted by `null` are kept as markers
+// until pruneDeleted is called.
+func (p *Provenance) mergeWithStrategy(src *Provenance, s MergeStrategy) {
+	if src == nil {
+		return
+	}
+
+	for k, srcChild := range src.Children {
+		if srcChild.IsLeaf() && srcChild.Value == nil && s.DeleteNulls {
+			srcChild = &Provenance{
+				Origin:    srcChild.Origin,
+				Value:     deleted{},
+				Overrides: srcChild.Overrides,
+			}
+		}
+
+		dstChild, ok := p.Children[k]
+		if ok && !dstChild.IsLeaf() && !srcChild.IsLeaf() {
+			dstChild.mergeWithStrategy(srcChild, s)
+			continue
+		}
+
+		if ok && dstChild.IsLeaf() && srcChild.IsLeaf() {
+			merged, ok := mergeListProvenance(dstChild, srcChild, s)
+			if ok {
+				p.Children[k] = merged
+				continue
+			}
+		}
+
+		if ok {
+			srcChild.Overrides = append(dstChild.history(), srcChild.Overrides...)
+		}
+
+		p.Children[k] = srcChild
+	}
+}
+
+// mergeListProvenance returns the provenance of the list of src merged into
+// the list of dst when the given strategy appends or merges lists by key.
+// Both origins are kept, as both contributed entries. It returns false when
+// the list of src replaces the value of dst.
+func mergeListProvenance(dst, src *Provenance, s MergeStrategy) (*Provenance, bool) {
+	if s.Lists != ListMergeAppend && s.Lists != ListMergeByKey {
+		return nil, false
+	}
+
+	dstList, ok := dst.Value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	srcList, ok := src.Value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	merged := &Provenance{
+		Origin:       src.Origin,
+		Value:        mergeLists(deepCopyValue(dstList).([]interface{}), srcList, s),
+		Contributors: append(dst.origins(), src.Contributors...),
+		Overrides:    slices.Concat(dst.Overrides, src.Overrides),
+	}
+
+	return merged, true
+}
+
+// pruneDeleted removes the provenance of values deleted by `null` of higher
+// layers, modifying it inplace like pruneDeleted does with the values.
+func (p *Provenance) pruneDeleted() {
+	for k, child := range p.Children {
+		if !child.IsLeaf() {
+			child.pruneDeleted()
+			continue
+		}
+
+		if _, ok := child.Value.(deleted); ok {
+			delete(p.Children, k)
+			continue
+		}
+
+		pruneDeletedValue(child.Value)
+	}
+}
@@ -0,0 +1,439 @@
+package values
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_MergeAllWithProvenance(t *testing.T) {
+	catalogOrigin := Origin{Kind: KindConfigMap, Name: "test-catalog-values", Namespace: "giantswarm", Priority: v1alpha1.ConfigPriorityCatalog, Layer: LayerCatalog}
+	extraOrigin := Origin{Kind: KindConfigMap, Name: "extra-values", Namespace: "giantswarm", Priority: v1alpha1.ConfigPriorityDefault, Layer: LayerExtra}
+	appOrigin := Origin{Kind: KindConfigMap, Name: "test-cluster-values", Namespace: "giantswarm", Priority: v1alpha1.ConfigPriorityCluster, Layer: LayerApp}
+	userOrigin := Origin{Kind: KindConfigMap, Name: "test-user-values", Namespace: "giantswarm", Priority: v1alpha1.ConfigPriorityUser, Layer: LayerUser}
+	userSecretOrigin := Origin{Kind: KindSecret, Name: "test-user-secrets", Namespace: "giantswarm", Priority: v1alpha1.ConfigPriorityUser, Layer: LayerUser}
+
+	tests := []struct {
+		name               string
+		app                v1alpha1.App
+		catalog            v1alpha1.Catalog
+		configMaps         []*corev1.ConfigMap
+		secrets            []*corev1.Secret
+		expectedData       map[string]interface{}
+		expectedProvenance map[string]*Provenance
+	}{
+		{
+			name: "case 0: provenance is empty when there is no config",
+			app: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-test-app",
+					Namespace: "giantswarm",
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:   "test-catalog",
+					Name:      "test-app",
+					Namespace: "giantswarm",
+				},
+			},
+			catalog:            v1alpha1.Catalog{},
+			expectedData:       nil,
+			expectedProvenance: map[string]*Provenance{},
+		},
+		{
+			name: "case 1: every layer is recorded",
+			app: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-test-app",
+					Namespace: "giantswarm",
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:   "test-catalog",
+					Name:      "test-app",
+					Namespace: "giantswarm",
+					Config: v1alpha1.AppSpecConfig{
+						ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+							Name:      "test-cluster-values",
+							Namespace: "giantswarm",
+						},
+					},
+					ExtraConfigs: []v1alpha1.AppExtraConfig{
+						{
+							Name:      "extra-values",
+							Namespace: "giantswarm",
+						},
+					},
+					UserConfig: v1alpha1.AppSpecUserConfig{
+						ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+							Name:      "test-user-values",
+							Namespace: "giantswarm",
+						},
+						Secret: v1alpha1.AppSpecUserConfigSecret{
+							Name:      "test-user-secrets",
+							Namespace: "giantswarm",
+						},
+					},
+				},
+			},
+			catalog: getSimpleTestCatalogDefinitionWithConfigMap(),
+			configMaps: []*corev1.ConfigMap{
+				getTestCatalogConfigMapDefinition(map[string]string{
+					"values": "a: catalog\nb: catalog\nnested:\n  p: catalog\n",
+				}),
+				getConfigMapDefinition("extra-values", "giantswarm", map[string]string{
+					"values": "a: extra\n",
+				}),
+				getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{
+					"values": "nested:\n  q: app\n",
+				}),
+				getConfigMapDefinition("test-user-values", "giantswarm", map[string]string{
+					"values": "a: user\nnested: flat\n",
+				}),
+			},
+			secrets: []*corev1.Secret{
+				getSecretDefinition("test-user-secrets", "giantswarm", map[string][]byte{
+					"secrets": []byte("a: secret\ns: secret\n"),
+				}),
+			},
+			expectedData: map[string]interface{}{
+				"a":      "secret",
+				"b":      "catalog",
+				"nested": "flat",
+				"s":      "secret",
+			},
+			expectedProvenance: map[string]*Provenance{
+				"a": {
+					Origin: &userSecretOrigin,
+					Value:  "secret",
+					Overrides: []Override{
+						{Origin: catalogOrigin, Value: "catalog"},
+						{Origin: extraOrigin, Value: "extra"},
+						{Origin: userOrigin, Value: "user"},
+					},
+				},
+				"b": {
+					Origin: &catalogOrigin,
+					Value:  "catalog",
+				},
+				"nested": {
+					Origin: &userOrigin,
+					Value:  "flat",
+					Overrides: []Override{
+						{Origin: catalogOrigin, Value: "catalog"},
+						{Origin: appOrigin, Value: "app"},
+					},
+				},
+				"s": {
+					Origin: &userSecretOrigin,
+					Value:  "secret",
+				},
+			},
+		},
+		{
+			name: "case 2: nested maps are merged",
+			app: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-test-app",
+					Namespace: "giantswarm",
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:   "test-catalog",
+					Name:      "test-app",
+					Namespace: "giantswarm",
+					Config: v1alpha1.AppSpecConfig{
+						ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+							Name:      "test-cluster-values",
+							Namespace: "giantswarm",
+						},
+					},
+				},
+			},
+			catalog: getSimpleTestCatalogDefinitionWithConfigMap(),
+			configMaps: []*corev1.ConfigMap{
+				getTestCatalogConfigMapDefinition(map[string]string{
+					"values": "nested:\n  p: catalog\n  q: catalog\n",
+				}),
+				getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{
+					"values": "nested:\n  q: app\n",
+				}),
+			},
+			expectedData: map[string]interface{}{
+				"nested": map[string]interface{}{
+					"p": "catalog",
+					"q": "app",
+				},
+			},
+			expectedProvenance: map[string]*Provenance{
+				"nested.p": {
+					Origin: &catalogOrigin,
+					Value:  "catalog",
+				},
+				"nested.q": {
+					Origin: &appOrigin,
+					Value:  "app",
+					Overrides: []Override{
+						{Origin: catalogOrigin, Value: "catalog"},
+					},
+				},
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			objs := make([]runtime.Object, 0)
+			for _, cm := range tc.configMaps {
+				objs = append(objs, cm)
+			}
+			for _, s := range tc.secrets {
+				objs = append(objs, s)
+			}
+
+			c := Config{
+				K8sClient: clientgofake.NewClientset(objs...),
+				Logger:    microloggertest.New(),
+			}
+			v, err := New(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			result, provenance, err := v.MergeAllWithProvenance(ctx, tc.app, tc.catalog)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expectedData) {
+				t.Fatalf("want matching data \n %s", cmp.Diff(result, tc.expectedData))
+			}
+
+			leaves := map[string]*Provenance{}
+			provenance.Walk(func(path []string, leaf *Provenance) {
+				leaves[strings.Join(path, ".")] = leaf
+			})
+
+			if !reflect.DeepEqual(leaves, tc.expectedProvenance) {
+				t.Fatalf("want matching provenance \n %s", cmp.Diff(leaves, tc.expectedProvenance))
+			}
+		})
+	}
+}
+
+func Test_ProvenanceLookup(t *testing.T) {
+	origin := Origin{Kind: KindConfigMap, Name: "test", Namespace: "giantswarm", Layer: LayerApp}
+
+	p := NewProvenance()
+	p.merge(newProvenance(origin, map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": "c",
+		},
+		"list": []interface{}{"x"},
+	}))
+
+	if leaf := p.Lookup("a", "b"); leaf == nil || leaf.Value != "c" || *leaf.Origin != origin {
+		t.Fatalf("want leaf with value %#q, got %#v", "c", leaf)
+	}
+	if node := p.Lookup("a"); node == nil || node.IsLeaf() {
+		t.Fatalf("want map node, got %#v", node)
+	}
+	if leaf := p.Lookup("list"); leaf == nil || !leaf.IsLeaf() {
+		t.Fatalf("want leaf for list, got %#v", leaf)
+	}
+	if node := p.Lookup("a", "b", "c"); node != nil {
+		t.Fatalf("want nil below a leaf, got %#v", node)
+	}
+	if node := p.Lookup("missing"); node != nil {
+		t.Fatalf("want nil for missing path, got %#v", node)
+	}
+}
+
+func Test_MergeAllWithProvenanceStrategies(t *testing.T) {
+	appOrigin := Origin{Kind: KindConfigMap, Name: "test-cluster-values", Namespace: "giantswarm", Priority: v1alpha1.ConfigPriorityCluster, Layer: LayerApp}
+	userOrigin := Origin{Kind: KindConfigMap, Name: "test-user-values", Namespace: "giantswarm", Priority: v1alpha1.ConfigPriorityUser, Layer: LayerUser}
+	userSecretOrigin := Origin{Kind: KindSecret, Name: "test-user-secrets", Namespace: "giantswarm", Priority: v1alpha1.ConfigPriorityUser, Layer: LayerUser}
+
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+				},
+			},
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+					Name:      "test-user-values",
+					Namespace: "giantswarm",
+				},
+				Secret: v1alpha1.AppSpecUserConfigSecret{
+					Name:      "test-user-secrets",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		strategy           MergeStrategy
+		appValues          string
+		userValues         string
+		userSecrets        string
+		expectedData       map[string]interface{}
+		expectedProvenance map[string]*Provenance
+	}{
+		{
+			name:        "case 0: lists are replaced",
+			appValues:   "l:\n- a\n",
+			userValues:  "l:\n- b\n",
+			userSecrets: "s: secret\n",
+			expectedData: map[string]interface{}{
+				"l": []interface{}{"b"},
+				"s": "secret",
+			},
+			expectedProvenance: map[string]*Provenance{
+				"l": {
+					Origin: &userOrigin,
+					Value:  []interface{}{"b"},
+					Overrides: []Override{
+						{Origin: appOrigin, Value: []interface{}{"a"}},
+					},
+				},
+				"s": {Origin: &userSecretOrigin, Value: "secret"},
+			},
+		},
+		{
+			name:        "case 1: appended lists are attributed to every layer",
+			strategy:    MergeStrategy{Lists: ListMergeAppend},
+			appValues:   "l:\n- a\n",
+			userValues:  "l:\n- b\n",
+			userSecrets: "l:\n- c\n",
+			expectedData: map[string]interface{}{
+				"l": []interface{}{"a", "b", "c"},
+			},
+			expectedProvenance: map[string]*Provenance{
+				"l": {
+					Origin:       &userSecretOrigin,
+					Value:        []interface{}{"a", "b", "c"},
+					Contributors: []Origin{appOrigin, userOrigin},
+				},
+			},
+		},
+		{
+			name:       "case 2: lists merged by key are attributed to every layer",
+			strategy:   MergeStrategy{Lists: ListMergeByKey, ListMergeKey: "name"},
+			appValues:  "env:\n- name: a\n  value: app\n",
+			userValues: "env:\n- name: a\n  value: user\n- name: b\n  value: user\n",
+			expectedData: map[string]interface{}{
+				"env": []interface{}{
+					map[string]interface{}{"name": "a", "value": "user"},
+					map[string]interface{}{"name": "b", "value": "user"},
+				},
+			},
+			expectedProvenance: map[string]*Provenance{
+				"env": {
+					Origin: &userOrigin,
+					Value: []interface{}{
+						map[string]interface{}{"name": "a", "value": "user"},
+						map[string]interface{}{"name": "b", "value": "user"},
+					},
+					Contributors: []Origin{appOrigin},
+				},
+			},
+		},
+		{
+			name:        "case 3: deleted values have no provenance",
+			strategy:    MergeStrategy{DeleteNulls: true},
+			appValues:   "a: app\nb: app\nc: app\nnested:\n  d: app\n",
+			userValues:  "a: null\nnested:\n  d: null\n",
+			userSecrets: "b: null\n",
+			expectedData: map[string]interface{}{
+				"c":      "app",
+				"nested": map[string]interface{}{},
+			},
+			expectedProvenance: map[string]*Provenance{
+				"c": {Origin: &appOrigin, Value: "app"},
+			},
+		},
+		{
+			name:       "case 4: values set after deletion keep the deletion as override",
+			strategy:   MergeStrategy{DeleteNulls: true},
+			appValues:  "a: app\n",
+			userValues: "a: null\n",
+			// The secret is merged after the configmaps were merged, so it
+			// sets the value deleted by the user configmap again.
+			userSecrets: "a: secret\n",
+			expectedData: map[string]interface{}{
+				"a": "secret",
+			},
+			expectedProvenance: map[string]*Provenance{
+				"a": {
+					Origin: &userSecretOrigin,
+					Value:  "secret",
+					Overrides: []Override{
+						{Origin: appOrigin, Value: "app"},
+						{Origin: userOrigin, Value: nil},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			objs := []runtime.Object{
+				getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{"values": tc.appValues}),
+				getConfigMapDefinition("test-user-values", "giantswarm", map[string]string{"values": tc.userValues}),
+				getSecretDefinition("test-user-secrets", "giantswarm", map[string][]byte{"secrets": []byte(tc.userSecrets)}),
+			}
+
+			c := Config{
+				K8sClient: clientgofake.NewClientset(objs...),
+				Logger:    microloggertest.New(),
+
+				MergeStrategy: tc.strategy,
+			}
+			v, err := New(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			result, provenance, err := v.MergeAllWithProvenance(ctx, app, v1alpha1.Catalog{})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expectedData) {
+				t.Fatalf("want matching data \n %s", cmp.Diff(result, tc.expectedData))
+			}
+
+			leaves := map[string]*Provenance{}
+			provenance.Walk(func(path []string, leaf *Provenance) {
+				leaves[strings.Join(path, ".")] = leaf
+			})
+
+			if !reflect.DeepEqual(leaves, tc.expectedProvenance) {
+				t.Fatalf("want matching provenance \n %s", cmp.Diff(leaves, tc.expectedProvenance))
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package values
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+// MergeAllRedacted works like MergeAll but replaces every value that comes
+// from a secret, or that overrode or was overridden by a value from a secret,
+// with MaskedValue. The result is safe to be logged.
+func (v *Values) MergeAllRedacted(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (map[string]interface{}, error) {
+	data, provenance, err := v.MergeAllWithProvenance(ctx, app, catalog)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	provenance.Walk(func(path []string, leaf *Provenance) {
+		if v.isSecretProvenance(leaf) {
+			redactValue(data, path)
+		}
+	})
+
+	return data, nil
+}
+
+// isSecretProvenance returns true when the given leaf, any origin
+// contributing to it or any value it overrode comes from a secret. It returns
+// false for nil.
+func (v *Values) isSecretProvenance(leaf *Provenance) bool {
+	if leaf == nil {
+		return false
+	}
+
+	if v.isSecretOrigin(leaf.Origin) {
+		return true
+	}
+
+	for _, o := range leaf.Contributors {
+		if v.isSecretOrigin(&o) {
+			return true
+		}
+	}
+
+	for _, o := range leaf.Overrides {
+		if v.isSecretOrigin(&o.Origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactValue replaces the value at the given path with MaskedValue, if there
+// is such a value.
+func redactValue(data map[string]interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+
+	current := data
+	for _, k := range path[:len(path)-1] {
+		nested, ok := current[k].(map[string]interface{})
+		if !ok {
+			return
+		}
+		current = nested
+	}
+
+	k := path[len(path)-1]
+	if _, ok := current[k]; ok {
+		current[k] = MaskedValue
+	}
+}
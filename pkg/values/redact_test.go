@@ -0,0 +1,81 @@
+package values
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_MergeAllRedacted(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+				},
+				Secret: v1alpha1.AppSpecConfigSecret{
+					Name:      "test-cluster-secrets",
+					Namespace: "giantswarm",
+				},
+			},
+			ExtraConfigs: []v1alpha1.AppExtraConfig{
+				{
+					Kind:      "secret",
+					Name:      "test-extra-secrets",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	k8sClient := clientgofake.NewClientset(
+		getConfigMapDefinition("test-cluster-values", "giantswarm", map[string]string{
+			"values": "replicas: 2\ndatabase:\n  host: db\n  password: plain\n",
+		}),
+		getSecretDefinition("test-cluster-secrets", "giantswarm", map[string][]byte{
+			"values": []byte("database:\n  password: secret\n"),
+		}),
+		getSecretDefinition("test-extra-secrets", "giantswarm", map[string][]byte{
+			"values": []byte("tokens:\n- a\n- b\n"),
+		}),
+	)
+
+	v, err := New(Config{
+		K8sClient: k8sClient,
+		Logger:    microloggertest.New(),
+	})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	result, err := v.MergeAllRedacted(context.Background(), app, v1alpha1.Catalog{})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	expectedData := map[string]interface{}{
+		"replicas": float64(2),
+		"database": map[string]interface{}{
+			"host":     "db",
+			"password": MaskedValue,
+		},
+		"tokens": MaskedValue,
+	}
+	if !reflect.DeepEqual(result, expectedData) {
+		t.Fatalf("want matching data \n %s", cmp.Diff(result, expectedData))
+	}
+}
@@ -0,0 +1,220 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	valueFromKey       = "valueFrom"
+	configMapKeyRefKey = "configMapKeyRef"
+	secretKeyRefKey    = "secretKeyRef"
+)
+
+// reference points to a key in a configmap or secret. References are maps
+// with `valueFrom` as their only key, e.g.
+//
+//	password:
+//	  valueFrom:
+//	    secretKeyRef:
+//	      name: shared-credentials
+//	      namespace: org-acme
+//	      key: password
+type reference struct {
+	kind      string
+	name      string
+	namespace string
+	key       string
+}
+
+func (r reference) String() string {
+	return fmt.Sprintf("key %#q of %s %#q in namespace %#q", r.key, r.kind, r.name, r.namespace)
+}
+
+// parseReference returns the reference the given value represents. It returns
+// false when the value is not a reference and fails with
+// invalidReferenceError when it is a malformed one.
+func parseReference(value interface{}) (reference, bool, error) {
+	m, ok := value.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return reference{}, false, nil
+	}
+	valueFrom, ok := m[valueFromKey]
+	if !ok {
+		return reference{}, false, nil
+	}
+
+	refs, ok := valueFrom.(map[string]interface{})
+	if !ok || len(refs) != 1 {
+		return reference{}, true, microerror.Maskf(invalidReferenceError, "%#q must have either %#q or %#q", valueFromKey, configMapKeyRefKey, secretKeyRefKey)
+	}
+
+	var r reference
+	var fields interface{}
+	switch {
+	case refs[configMapKeyRefKey] != nil:
+		r.kind = KindConfigMap
+		fields = refs[configMapKeyRefKey]
+	case refs[secretKeyRefKey] != nil:
+		r.kind = KindSecret
+		fields = refs[secretKeyRefKey]
+	default:
+		return reference{}, true, microerror.Maskf(invalidReferenceError, "%#q must have either %#q or %#q", valueFromKey, configMapKeyRefKey, secretKeyRefKey)
+	}
+
+	f, ok := fields.(map[string]interface{})
+	if !ok {
+		return reference{}, true, microerror.Maskf(invalidReferenceError, "%#q of %#q must be a map", r.kind+"KeyRef", valueFromKey)
+	}
+	for k, field := range map[string]*string{"name": &r.name, "namespace": &r.namespace, "key": &r.key} {
+		s, ok := f[k].(string)
+		if f[k] != nil && !ok {
+			return reference{}, true, microerror.Maskf(invalidReferenceError, "%#q of reference must be a string", k)
+		}
+		*field = s
+	}
+
+	if r.name == "" || r.key == "" {
+		return reference{}, true, microerror.Maskf(invalidReferenceError, "reference must have a name and a key")
+	}
+
+	return r, true, nil
+}
+
+// resolveReferences replaces all references in data by the values they point
+// to, modifying data inplace. Nothing is done unless Config.ResolveReferences
+// is set. When provenance is not nil the referenced objects are recorded as
+// the origins of the resolved values. Unless secrets is set, references to
+// secrets fail with invalidReferenceError, so that secret values never end
+// up in values merged from configmaps only.
+func (v *Values) resolveReferences(ctx context.Context, app v1alpha1.App, data map[string]interface{}, provenance *Provenance, secrets bool) error {
+	if !v.references {
+		return nil
+	}
+
+	err := v.resolveReferencesIn(ctx, app, nil, data, provenance, secrets)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (v *Values) resolveReferencesIn(ctx context.Context, app v1alpha1.App, path []string, value interface{}, provenance *Provenance, secrets bool) error {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for k, child := range typed {
+			resolved, err := v.resolveValue(ctx, app, appendPath(path, k), child, provenance, secrets)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			typed[k] = resolved
+		}
+	case []interface{}:
+		for i, child := range typed {
+			resolved, err := v.resolveValue(ctx, app, appendPath(path, strconv.Itoa(i)), child, provenance, secrets)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			typed[i] = resolved
+		}
+	}
+
+	return nil
+}
+
+func (v *Values) resolveValue(ctx context.Context, app v1alpha1.App, path []string, value interface{}, provenance *Provenance, secrets bool) (interface{}, error) {
+	r, ok, err := parseReference(value)
+	if err != nil {
+		return nil, microerror.Maskf(invalidReferenceError, "invalid reference at %#q, logs: %s", formatPath(path), err.Error())
+	}
+	if !ok {
+		err = v.resolveReferencesIn(ctx, app, path, value, provenance, secrets)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		return value, nil
+	}
+
+	resolved, o, err := v.resolveReference(ctx, app, r, nil, secrets)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if provenance != nil {
+		provenance.replace(path, o, resolved)
+	}
+
+	return resolved, nil
+}
+
+// resolveReference returns the value the given reference points to and the
+// origin it was read from. The value is the raw string stored under the key,
+// unless that is a reference itself, which is then followed. chain holds the
+// references followed so far to detect cycles. References to secrets fail
+// unless secrets is set.
+func (v *Values) resolveReference(ctx context.Context, app v1alpha1.App, r reference, chain []reference, secrets bool) (string, Origin, error) {
+	if r.namespace == "" {
+		r.namespace = app.Namespace
+	}
+	if !secrets && v.isSecretKind(r.kind) {
+		return "", Origin{}, microerror.Maskf(invalidReferenceError, "reference to %s is not allowed in configmap values, secrets may only be referenced when values are merged with secrets", r)
+	}
+	if r.namespace != app.Namespace && !slices.Contains(v.referenceNamespaces, r.namespace) {
+		return "", Origin{}, microerror.Maskf(invalidReferenceError, "reference to %s is not allowed, only namespace %#q and %v may be referenced", r, app.Namespace, v.referenceNamespaces)
+	}
+
+	if slices.Contains(chain, r) {
+		var refs []string
+		for _, c := range append(chain, r) {
+			refs = append(refs, c.String())
+		}
+		return "", Origin{}, microerror.Maskf(invalidReferenceError, "reference cycle: %s", strings.Join(refs, " -> "))
+	}
+	chain = append(chain, r)
+
+	source, ok := v.valueSources[r.kind]
+	if !ok {
+		return "", Origin{}, microerror.Maskf(unknownKindError, "no value source registered for kind %#q", r.kind)
+	}
+
+	data, err := source.Get(ctx, r.name, r.namespace)
+	if IsNotFound(err) || apierrors.IsNotFound(err) {
+		return "", Origin{}, microerror.Maskf(notFoundError, "%s %#q in namespace %#q not found", r.kind, r.name, r.namespace)
+	} else if err != nil {
+		return "", Origin{}, microerror.Mask(err)
+	}
+
+	raw, ok := data[r.key]
+	if !ok {
+		return "", Origin{}, microerror.Maskf(notFoundError, "%s not found", r)
+	}
+
+	var nested map[string]interface{}
+	if yaml.Unmarshal([]byte(raw), &nested) == nil {
+		next, ok, err := parseReference(nested)
+		if err != nil {
+			return "", Origin{}, microerror.Maskf(invalidReferenceError, "invalid reference in %s, logs: %s", r, err.Error())
+		}
+		if ok {
+			return v.resolveReference(ctx, app, next, chain, secrets)
+		}
+	}
+
+	o := Origin{
+		Kind:      r.kind,
+		Name:      r.name,
+		Namespace: r.namespace,
+		Layer:     LayerReference,
+	}
+
+	return raw, o, nil
+}
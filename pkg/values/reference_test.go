@@ -0,0 +1,299 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ResolveReferences(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "org-acme",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+					Name:      "test-user-values",
+					Namespace: "org-acme",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		resolveReferences   bool
+		referenceNamespaces []string
+		values              string
+		expectedData        map[string]interface{}
+		errorMatcher        func(error) bool
+	}{
+		{
+			name:              "case 0: references are kept when not resolved",
+			resolveReferences: false,
+			values:            "password:\n  valueFrom:\n    secretKeyRef:\n      name: shared-credentials\n      key: password\n",
+			expectedData: map[string]interface{}{
+				"password": map[string]interface{}{
+					"valueFrom": map[string]interface{}{
+						"secretKeyRef": map[string]interface{}{
+							"name": "shared-credentials",
+							"key":  "password",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:              "case 1: secret and configmap references in maps and lists",
+			resolveReferences: true,
+			values: "password:\n  valueFrom:\n    secretKeyRef:\n      name: shared-credentials\n      key: password\n" +
+				"hosts:\n- valueFrom:\n    configMapKeyRef:\n      name: shared-config\n      namespace: giantswarm\n      key: host\n" +
+				"env:\n- name: TOKEN\n  valueFrom:\n    secretKeyRef:\n      name: chart-managed\n      key: token\n",
+			referenceNamespaces: []string{"giantswarm"},
+			expectedData: map[string]interface{}{
+				"password": "0123",
+				"hosts":    []interface{}{"example.com"},
+				// Maps with more keys than `valueFrom` are no references.
+				"env": []interface{}{
+					map[string]interface{}{
+						"name": "TOKEN",
+						"valueFrom": map[string]interface{}{
+							"secretKeyRef": map[string]interface{}{
+								"name": "chart-managed",
+								"key":  "token",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:              "case 2: chained references",
+			resolveReferences: true,
+			values:            "token:\n  valueFrom:\n    secretKeyRef:\n      name: shared-credentials\n      key: alias\n",
+			expectedData: map[string]interface{}{
+				"token": "0123",
+			},
+		},
+		{
+			name:              "case 3: reference cycle",
+			resolveReferences: true,
+			values:            "token:\n  valueFrom:\n    secretKeyRef:\n      name: shared-credentials\n      key: cycle-a\n",
+			errorMatcher:      IsInvalidReference,
+		},
+		{
+			name:              "case 4: namespace not allowed",
+			resolveReferences: true,
+			values:            "host:\n  valueFrom:\n    configMapKeyRef:\n      name: shared-config\n      namespace: giantswarm\n      key: host\n",
+			errorMatcher:      IsInvalidReference,
+		},
+		{
+			name:              "case 5: missing key",
+			resolveReferences: true,
+			values:            "password:\n  valueFrom:\n    secretKeyRef:\n      name: shared-credentials\n      key: missing\n",
+			errorMatcher:      IsNotFound,
+		},
+		{
+			name:              "case 6: malformed reference",
+			resolveReferences: true,
+			values:            "password:\n  valueFrom:\n    secretKeyRef:\n      name: shared-credentials\n",
+			errorMatcher:      IsInvalidReference,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClient := clientgofake.NewClientset(
+				getConfigMapDefinition("test-user-values", "org-acme", map[string]string{
+					"values": tc.values,
+				}),
+				getConfigMapDefinition("shared-config", "giantswarm", map[string]string{
+					"host": "example.com",
+				}),
+				getSecretDefinition("shared-credentials", "org-acme", map[string][]byte{
+					"password": []byte("0123"),
+					"alias":    []byte("valueFrom:\n  secretKeyRef:\n    name: shared-credentials\n    key: password\n"),
+					"cycle-a":  []byte("valueFrom:\n  secretKeyRef:\n    name: shared-credentials\n    key: cycle-b\n"),
+					"cycle-b":  []byte("valueFrom:\n  secretKeyRef:\n    name: shared-credentials\n    key: cycle-a\n"),
+				}),
+			)
+
+			v, err := New(Config{
+				K8sClient:           k8sClient,
+				Logger:              microloggertest.New(),
+				ReferenceNamespaces: tc.referenceNamespaces,
+				ResolveReferences:   tc.resolveReferences,
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			result, err := v.MergeAll(context.Background(), app, v1alpha1.Catalog{})
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expectedData) {
+				t.Fatalf("want matching data \n %s", cmp.Diff(result, tc.expectedData))
+			}
+		})
+	}
+}
+
+func Test_ResolveReferencesRedacted(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "org-acme",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+					Name:      "test-user-values",
+					Namespace: "org-acme",
+				},
+			},
+		},
+	}
+
+	k8sClient := clientgofake.NewClientset(
+		getConfigMapDefinition("test-user-values", "org-acme", map[string]string{
+			"values": "replicas: 2\npassword:\n  valueFrom:\n    secretKeyRef:\n      name: shared-credentials\n      key: password\n",
+		}),
+		getSecretDefinition("shared-credentials", "org-acme", map[string][]byte{
+			"password": []byte("secret"),
+		}),
+	)
+
+	v, err := New(Config{
+		K8sClient:         k8sClient,
+		Logger:            microloggertest.New(),
+		ResolveReferences: true,
+	})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	// Values read from secrets through references are secret values too.
+	result, err := v.MergeAllRedacted(context.Background(), app, v1alpha1.Catalog{})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	expectedData := map[string]interface{}{
+		"replicas": float64(2),
+		"password": MaskedValue,
+	}
+	if !reflect.DeepEqual(result, expectedData) {
+		t.Fatalf("want matching data \n %s", cmp.Diff(result, expectedData))
+	}
+}
+
+func Test_ResolveReferencesInConfigMapData(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "org-acme",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+					Name:      "test-user-values",
+					Namespace: "org-acme",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		values       string
+		expectedData map[string]interface{}
+		errorMatcher func(error) bool
+	}{
+		{
+			name:   "case 0: configmap references are resolved",
+			values: "host:\n  valueFrom:\n    configMapKeyRef:\n      name: shared-config\n      key: host\n",
+			expectedData: map[string]interface{}{
+				"host": "example.com",
+			},
+		},
+		{
+			name:         "case 1: secret references are not allowed",
+			values:       "password:\n  valueFrom:\n    secretKeyRef:\n      name: s\n      key: p\n",
+			errorMatcher: IsInvalidReference,
+		},
+		{
+			name:         "case 2: configmap references chained to secrets are not allowed",
+			values:       "password:\n  valueFrom:\n    configMapKeyRef:\n      name: shared-config\n      key: alias\n",
+			errorMatcher: IsInvalidReference,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClient := clientgofake.NewClientset(
+				getConfigMapDefinition("test-user-values", "org-acme", map[string]string{
+					"values": tc.values,
+				}),
+				getConfigMapDefinition("shared-config", "org-acme", map[string]string{
+					"host":  "example.com",
+					"alias": "valueFrom:\n  secretKeyRef:\n    name: s\n    key: p\n",
+				}),
+				getSecretDefinition("s", "org-acme", map[string][]byte{
+					"p": []byte("hunter2"),
+				}),
+			)
+
+			v, err := New(Config{
+				K8sClient:         k8sClient,
+				Logger:            microloggertest.New(),
+				ResolveReferences: true,
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			result, err := v.MergeConfigMapData(context.Background(), app, v1alpha1.Catalog{})
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expectedData) {
+				t.Fatalf("want matching data \n %s", cmp.Diff(result, tc.expectedData))
+			}
+			if strings.Contains(fmt.Sprint(result), "hunter2") {
+				t.Fatalf("want no secret values in configmap data, got %v", result)
+			}
+		})
+	}
+}
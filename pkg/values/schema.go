@@ -0,0 +1,137 @@
+package values
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const (
+	schemaURL = "values.schema.json"
+)
+
+// SchemaViolation is a value not matching the values schema of a chart.
+type SchemaViolation struct {
+	// Path is the path of the value, e.g. `["ingress", "hosts", "0"]`. It is
+	// empty for violations of the values as a whole.
+	Path []string `json:"path"`
+	// Message describes the violation.
+	Message string `json:"message"`
+	// Origin is the layer that supplied the value. It is nil when no
+	// provenance is given or the violation is about a map of values, e.g. a
+	// missing required property.
+	Origin *Origin `json:"origin,omitempty"`
+}
+
+func (v SchemaViolation) String() string {
+	path := formatPath(v.Path)
+	if path == "" {
+		path = "."
+	}
+
+	if v.Origin == nil {
+		return fmt.Sprintf("%s: %s", path, v.Message)
+	}
+
+	return fmt.Sprintf("%s: %s (set by %s)", path, v.Message, v.Origin)
+}
+
+// ValidateSchema validates merged values against the given JSON Schema
+// document, usually the `values.schema.json` of the chart. It returns all
+// violations sorted by path. When provenance is not nil, e.g. as returned by
+// MergeAllWithProvenance, violations name the layer that supplied the bad
+// value. An invalid schema fails with parsingError.
+func ValidateSchema(values map[string]interface{}, schema []byte, provenance *Provenance) ([]SchemaViolation, error) {
+	compiled, err := compileSchema(schema)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	// Round trip the values through JSON so they hold the types the
+	// validator expects.
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	err = compiled.Validate(instance)
+	if err == nil {
+		return nil, nil
+	}
+
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, microerror.Mask(err)
+	}
+
+	printer := message.NewPrinter(language.English)
+
+	var violations []SchemaViolation
+	for _, leaf := range leafValidationErrors(validationErr) {
+		violation := SchemaViolation{
+			Path:    leaf.InstanceLocation,
+			Message: leaf.ErrorKind.LocalizedString(printer),
+		}
+		if provenance != nil {
+			violation.Origin = provenance.originOf(leaf.InstanceLocation)
+		}
+
+		violations = append(violations, violation)
+	}
+
+	slices.SortStableFunc(violations, func(a, b SchemaViolation) int {
+		return slices.Compare(a.Path, b.Path)
+	})
+
+	return violations, nil
+}
+
+func compileSchema(schema []byte) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, microerror.Maskf(parsingError, "failed to parse values schema, logs: %s", err.Error())
+	}
+
+	compiler := jsonschema.NewCompiler()
+
+	err = compiler.AddResource(schemaURL, doc)
+	if err != nil {
+		return nil, microerror.Maskf(parsingError, "failed to parse values schema, logs: %s", err.Error())
+	}
+
+	compiled, err := compiler.Compile(schemaURL)
+	if err != nil {
+		return nil, microerror.Maskf(parsingError, "failed to compile values schema, logs: %s", strings.TrimSpace(err.Error()))
+	}
+
+	return compiled, nil
+}
+
+// leafValidationErrors returns the errors without further causes, which are
+// the actual violations.
+func leafValidationErrors(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		leaves = append(leaves, leafValidationErrors(cause)...)
+	}
+
+	return leaves
+}
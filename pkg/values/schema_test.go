@@ -0,0 +1,125 @@
+package values
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["image"],
+  "properties": {
+    "replicas": {"type": "integer", "minimum": 1},
+    "ingress": {
+      "type": "object",
+      "properties": {
+        "hosts": {"type": "array", "items": {"type": "string"}}
+      }
+    }
+  }
+}`
+
+func Test_ValidateSchema(t *testing.T) {
+	catalog := Origin{Kind: KindConfigMap, Name: "test-catalog-values", Namespace: "giantswarm", Layer: LayerCatalog}
+	user := Origin{Kind: KindConfigMap, Name: "test-user-values", Namespace: "giantswarm", Priority: 100, Layer: LayerUser}
+
+	provenance := NewProvenance()
+	provenance.merge(newProvenance(catalog, map[string]interface{}{
+		"replicas": float64(1),
+		"ingress": map[string]interface{}{
+			"hosts": []interface{}{"a"},
+		},
+	}))
+	provenance.merge(newProvenance(user, map[string]interface{}{
+		"replicas": float64(0),
+		"ingress": map[string]interface{}{
+			"hosts": []interface{}{"a", float64(2)},
+		},
+	}))
+
+	tests := []struct {
+		name               string
+		values             map[string]interface{}
+		schema             string
+		provenance         *Provenance
+		expectedViolations []SchemaViolation
+		errorMatcher       func(error) bool
+	}{
+		{
+			name: "case 0: valid values",
+			values: map[string]interface{}{
+				"image":    "quay.io/giantswarm/test",
+				"replicas": float64(2),
+			},
+			schema: testSchema,
+		},
+		{
+			name: "case 1: violations with origins",
+			values: map[string]interface{}{
+				"replicas": float64(0),
+				"ingress": map[string]interface{}{
+					"hosts": []interface{}{"a", float64(2)},
+				},
+			},
+			schema:     testSchema,
+			provenance: provenance,
+			expectedViolations: []SchemaViolation{
+				{
+					Path:    []string{},
+					Message: "missing property 'image'",
+				},
+				{
+					Path:    []string{"ingress", "hosts", "1"},
+					Message: "got number, want string",
+					Origin:  &user,
+				},
+				{
+					Path:    []string{"replicas"},
+					Message: "minimum: got 0, want 1",
+					Origin:  &user,
+				},
+			},
+		},
+		{
+			name: "case 2: violations without provenance",
+			values: map[string]interface{}{
+				"image":    "quay.io/giantswarm/test",
+				"replicas": "two",
+			},
+			schema: testSchema,
+			expectedViolations: []SchemaViolation{
+				{
+					Path:    []string{"replicas"},
+					Message: "got string, want integer",
+				},
+			},
+		},
+		{
+			name:         "case 3: invalid schema",
+			values:       map[string]interface{}{},
+			schema:       `{"type": 1}`,
+			errorMatcher: IsParsingError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			violations, err := ValidateSchema(tc.values, []byte(tc.schema), tc.provenance)
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !reflect.DeepEqual(violations, tc.expectedViolations) {
+				t.Fatalf("want matching violations \n %s", cmp.Diff(violations, tc.expectedViolations))
+			}
+		})
+	}
+}
@@ -6,9 +6,10 @@ import (
 
 	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
 	"github.com/giantswarm/microerror"
-	"github.com/imdario/mergo"
+	"github.com/giantswarm/micrologger"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/giantswarm/app/v8/pkg/key"
 )
@@ -16,95 +17,141 @@ import (
 // MergeSecretData merges the data from the catalog, app, user and extra config secrets
 // and returns a single set of values.
 func (v *Values) MergeSecretData(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (map[string]interface{}, error) {
-	appSecretName := key.AppSecretName(app)
-	catalogSecretName := key.CatalogSecretName(catalog)
-	userSecretName := key.UserSecretName(app)
+	data, err := v.mergeSecretData(ctx, app, catalog, nil, nil)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
 
-	extraConfigs := key.SecretExtraConfigs(app)
+	return data, nil
+}
 
-	if appSecretName == "" && catalogSecretName == "" && userSecretName == "" && len(extraConfigs) == 0 {
-		// Return early as there is no secret.
-		return nil, nil
+// MergeSecretDataWithProvenance works like MergeSecretData but also returns
+// the provenance of every merged value.
+func (v *Values) MergeSecretDataWithProvenance(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (map[string]interface{}, *Provenance, error) {
+	provenance := NewProvenance()
+
+	data, err := v.mergeSecretData(ctx, app, catalog, provenance, nil)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
 	}
 
-	// We get the catalog level secrets if configured.
-	rawCatalogData, err := v.getSecretDataForCatalog(ctx, catalog)
+	return data, provenance, nil
+}
+
+// mergeSecretData merges the secret values. When provenance is not nil the
+// origin of every merged value is recorded in it and when warnings is not nil
+// the warnings found are appended to it.
+func (v *Values) mergeSecretData(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog, provenance *Provenance, warnings *[]Warning) (map[string]interface{}, error) {
+	origins, err := v.secretOrigins(app, catalog)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	catalogData, err := extractData(secret, "catalog", toStringMap(rawCatalogData))
+	// Secrets are merged and in case of intersecting values the later layers
+	// are preferred.
+	layers, err := v.fetchLayers(ctx, app, origins)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	if catalogData == nil {
-		// If there is no catalog data then treat it as an empty map otherwise `mergo.Merge` will silently
-		// fail to merge the first layers: `dst = nil; mergo.Merge(dst, MAP_OF_DATA)` and `dst` is still nil
-		catalogData = map[string]interface{}{}
+	if warnings != nil {
+		*warnings = append(*warnings, skippedLayers(layers)...)
 	}
 
-	// Pre cluster extra secrets
-	err = v.fetchAndMergeExtraConfigs(ctx, getPreClusterExtraSecretEntries(extraConfigs), v.getSecretAsString, catalogData)
+	data, err := v.mergeLayers(ctx, layers, provenance)
 	if err != nil {
-		return nil, err
+		return nil, microerror.Mask(err)
+	}
+
+	pruneDeleted(data)
+	if provenance != nil {
+		provenance.pruneDeleted()
 	}
 
-	// We get the app level secrets if configured.
-	rawAppData, err := v.getSecretDataForApp(ctx, app)
+	err = v.interpolate(app, data)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	appData, err := extractData(secret, "app", toStringMap(rawAppData))
+	err = v.resolveReferences(ctx, app, data, provenance, true)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	// Secrets are merged and in case of intersecting values the app level
-	// secrets are preferred.
-	err = mergo.Merge(&catalogData, appData, mergo.WithOverride)
+	err = v.checkTotalSize(app, data)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	// Post cluster / pre user extra secrets
-	err = v.fetchAndMergeExtraConfigs(ctx, getPostClusterPreUserExtraSecretEntries(extraConfigs), v.getSecretAsString, catalogData)
+	return data, nil
+}
+
+// secretOrigins returns the secrets feeding the values of the given app in
+// the order they are merged: catalog, pre cluster extra configs, app, post
+// cluster / pre user extra configs, user and post user extra configs.
+func (v *Values) secretOrigins(app v1alpha1.App, catalog v1alpha1.Catalog) ([]Origin, error) {
+	extraConfigs, err := v.extraConfigs(app, true)
 	if err != nil {
-		return nil, err
+		return nil, microerror.Mask(err)
+	}
+
+	if key.AppSecretName(app) == "" && key.CatalogSecretName(catalog) == "" && key.UserSecretName(app) == "" && len(extraConfigs) == 0 {
+		return nil, nil
 	}
 
-	// We get the user level values if configured and merge them.
-	if userSecretName != "" {
-		rawUserData, err := v.getUserSecretDataForApp(ctx, app)
-		if err != nil {
-			return nil, microerror.Mask(err)
-		}
+	var origins []Origin
+
+	if key.CatalogSecretName(catalog) != "" {
+		origins = append(origins, Origin{
+			Kind:      KindSecret,
+			Name:      key.CatalogSecretName(catalog),
+			Namespace: key.CatalogSecretNamespace(catalog),
+			Priority:  v1alpha1.ConfigPriorityCatalog,
+			Layer:     LayerCatalog,
+		})
+	}
 
-		// Secrets are merged again and in case of intersecting values the user
-		// level secrets are preferred.
-		userData, err := extractData(secret, "user", toStringMap(rawUserData))
-		if err != nil {
-			return nil, microerror.Mask(err)
-		}
+	origins = append(origins, extraConfigOrigins(getPreClusterExtraConfigs(extraConfigs, v.isSecretKind))...)
 
-		err = mergo.Merge(&catalogData, userData, mergo.WithOverride)
-		if err != nil {
-			return nil, microerror.Mask(err)
-		}
+	if key.AppSecretName(app) != "" {
+		origins = append(origins, Origin{
+			Kind:      KindSecret,
+			Name:      key.AppSecretName(app),
+			Namespace: key.AppSecretNamespace(app),
+			Priority:  v1alpha1.ConfigPriorityCluster,
+			Layer:     LayerApp,
+		})
 	}
 
-	// Post user extra secrets
-	err = v.fetchAndMergeExtraConfigs(ctx, getPostUserExtraSecretEntries(extraConfigs), v.getSecretAsString, catalogData)
-	if err != nil {
-		return nil, err
+	origins = append(origins, extraConfigOrigins(getPostClusterPreUserExtraConfigs(extraConfigs, v.isSecretKind))...)
+
+	if key.UserSecretName(app) != "" {
+		origins = append(origins, Origin{
+			Kind:      KindSecret,
+			Name:      key.UserSecretName(app),
+			Namespace: key.UserSecretNamespace(app),
+			Priority:  v1alpha1.ConfigPriorityUser,
+			Layer:     LayerUser,
+		})
 	}
 
-	return catalogData, nil
+	origins = append(origins, extraConfigOrigins(getPostUserExtraConfigs(extraConfigs, v.isSecretKind))...)
+
+	return origins, nil
+}
+
+// secretSource is the ValueSource reading values from secrets.
+type secretSource struct {
+	k8sClient kubernetes.Interface
+	logger    micrologger.Logger
+}
+
+func (s *secretSource) IsSecret() bool {
+	return true
 }
 
-func (v *Values) getSecretAsString(ctx context.Context, secretName, secretNamespace string) (map[string]string, error) {
-	data, err := v.getSecret(ctx, secretName, secretNamespace)
+func (s *secretSource) Get(ctx context.Context, secretName, secretNamespace string) (map[string]string, error) {
+	data, err := s.getSecret(ctx, secretName, secretNamespace)
 
 	if err != nil {
 		return nil, err
@@ -113,49 +160,22 @@ func (v *Values) getSecretAsString(ctx context.Context, secretName, secretNamesp
 	return toStringMap(data), nil
 }
 
-func (v *Values) getSecret(ctx context.Context, secretName, secretNamespace string) (map[string][]byte, error) {
+func (s *secretSource) getSecret(ctx context.Context, secretName, secretNamespace string) (map[string][]byte, error) {
 	if secretName == "" {
 		// Return early as no secret has been specified.
 		return nil, nil
 	}
 
-	v.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("looking for secret %#q in namespace %#q", secretName, secretNamespace))
+	s.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("looking for secret %#q in namespace %#q", secretName, secretNamespace))
 
-	secret, err := v.k8sClient.CoreV1().Secrets(secretNamespace).Get(ctx, secretName, metav1.GetOptions{})
+	secret, err := s.k8sClient.CoreV1().Secrets(secretNamespace).Get(ctx, secretName, metav1.GetOptions{})
 	if apierrors.IsNotFound(err) {
 		return nil, microerror.Maskf(notFoundError, "secret %#q in namespace %#q not found", secretName, secretNamespace)
 	} else if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	v.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found secret %#q in namespace %#q", secretName, secretNamespace))
+	s.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found secret %#q in namespace %#q", secretName, secretNamespace))
 
 	return secret.Data, nil
 }
-
-func (v *Values) getSecretDataForApp(ctx context.Context, app v1alpha1.App) (map[string][]byte, error) {
-	secret, err := v.getSecret(ctx, key.AppSecretName(app), key.AppSecretNamespace(app))
-	if err != nil {
-		return nil, microerror.Mask(err)
-	}
-
-	return secret, nil
-}
-
-func (v *Values) getSecretDataForCatalog(ctx context.Context, catalog v1alpha1.Catalog) (map[string][]byte, error) {
-	secret, err := v.getSecret(ctx, key.CatalogSecretName(catalog), key.CatalogSecretNamespace(catalog))
-	if err != nil {
-		return nil, microerror.Mask(err)
-	}
-
-	return secret, nil
-}
-
-func (v *Values) getUserSecretDataForApp(ctx context.Context, app v1alpha1.App) (map[string][]byte, error) {
-	secret, err := v.getSecret(ctx, key.UserSecretName(app), key.UserSecretNamespace(app))
-	if err != nil {
-		return nil, microerror.Mask(err)
-	}
-
-	return secret, nil
-}
@@ -0,0 +1,61 @@
+package values
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/app/v8/pkg/key"
+)
+
+// ValueSource is a backend values are read from. Sources are registered by
+// the kind used in `.spec.extraConfigs` of App CRs. The built-in sources are
+// registered for KindConfigMap and KindSecret and are also used for the
+// catalog, app and user layers.
+type ValueSource interface {
+	// Get returns the data of the object with the given name and namespace.
+	// The data is expected to contain YAML values. When the object does not
+	// exist, the returned error must either be matched by IsNotFound or by
+	// `apierrors.IsNotFound` from k8s.io/apimachinery.
+	Get(ctx context.Context, name, namespace string) (map[string]string, error)
+	// IsSecret returns true when the values are sensitive. They are then
+	// merged with the secret values instead of the configmap values.
+	IsSecret() bool
+}
+
+// extraConfigs returns the extra configs of the given app that are merged
+// with either the secret or the configmap values. It fails when an extra
+// config uses a kind no value source is registered for.
+func (v *Values) extraConfigs(app v1alpha1.App, isSecret bool) ([]v1alpha1.AppExtraConfig, error) {
+	extraConfigs := []v1alpha1.AppExtraConfig{}
+
+	for _, entry := range key.ExtraConfigs(app) {
+		source, ok := v.valueSources[considerDefaultKind(entry.Kind)]
+		if !ok {
+			return nil, microerror.Maskf(unknownKindError, "no value source registered for kind %#q of extra config %#q in namespace %#q", entry.Kind, entry.Name, entry.Namespace)
+		}
+
+		if source.IsSecret() == isSecret {
+			extraConfigs = append(extraConfigs, entry)
+		}
+	}
+
+	return extraConfigs, nil
+}
+
+func (v *Values) isConfigMapKind(kind string) bool {
+	source, ok := v.valueSources[kind]
+	return ok && !source.IsSecret()
+}
+
+func (v *Values) isSecretKind(kind string) bool {
+	source, ok := v.valueSources[kind]
+	return ok && source.IsSecret()
+}
+
+// isSecretOrigin returns true when the values of the given origin come from a
+// secret.
+func (v *Values) isSecretOrigin(o *Origin) bool {
+	return o != nil && v.isSecretKind(o.Kind)
+}
@@ -0,0 +1,74 @@
+package values
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/giantswarm/microerror"
+)
+
+var yamlPositionRegexp = regexp.MustCompile(`line (\d+)(?:: column (\d+)|, column (\d+))?`)
+
+// SourceError is the error returned when the values of a single configmap or
+// secret can not be read, parsed or exceed limits. Callers get it with
+// `errors.As` to find out which layer failed, while matchers like
+// IsNotFound and IsParsingError keep working on it.
+type SourceError struct {
+	// Origin identifies the failing configmap or secret and its layer and
+	// priority.
+	Origin Origin
+	// Key is the data key that failed to parse, if any.
+	Key string
+	// Line and Column are the position of YAML parsing errors. They are zero
+	// when unknown.
+	Line   int
+	Column int
+
+	err error
+}
+
+func (e *SourceError) Error() string {
+	return e.err.Error()
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.err
+}
+
+// newSourceError returns err as a SourceError of the given origin unless it
+// already is one.
+func newSourceError(o Origin, err error) error {
+	var sourceErr *SourceError
+	if errors.As(err, &sourceErr) {
+		return err
+	}
+
+	return &SourceError{
+		Origin: o,
+		err:    err,
+	}
+}
+
+// newYAMLSourceError returns a SourceError for the YAML error parsing the
+// given key of the given origin, taking the position from the message of the
+// YAML parser.
+func newYAMLSourceError(o Origin, k string, yamlErr error) error {
+	sourceErr := &SourceError{
+		Origin: o,
+		Key:    k,
+		err:    microerror.Maskf(parsingError, "failed to parse key %#q of %s, logs: %s", k, o, yamlErr.Error()),
+	}
+
+	matches := yamlPositionRegexp.FindStringSubmatch(yamlErr.Error())
+	if matches != nil {
+		sourceErr.Line, _ = strconv.Atoi(matches[1])
+		for _, column := range matches[2:] {
+			if column != "" {
+				sourceErr.Column, _ = strconv.Atoi(column)
+			}
+		}
+	}
+
+	return sourceErr
+}
@@ -0,0 +1,100 @@
+package values
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_SourceError(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			ExtraConfigs: []v1alpha1.AppExtraConfig{
+				{
+					Kind:      "configMap",
+					Name:      "test-extra-values",
+					Namespace: "giantswarm",
+					Priority:  120,
+				},
+			},
+		},
+	}
+
+	extraOrigin := Origin{Kind: KindConfigMap, Name: "test-extra-values", Namespace: "giantswarm", Priority: 120, Layer: LayerExtra}
+
+	tests := []struct {
+		name                string
+		values              map[string]string
+		expectedSourceError SourceError
+		errorMatcher        func(error) bool
+	}{
+		{
+			name:                "case 0: missing source",
+			expectedSourceError: SourceError{Origin: extraOrigin},
+			errorMatcher:        IsNotFound,
+		},
+		{
+			name: "case 1: invalid YAML",
+			values: map[string]string{
+				"values": "a: 1\nb: [\n",
+			},
+			expectedSourceError: SourceError{Origin: extraOrigin, Key: "values", Line: 2},
+			errorMatcher:        IsParsingError,
+		},
+		{
+			name: "case 2: invalid mapping",
+			values: map[string]string{
+				"values": "a: 1\n  b: 2\n",
+			},
+			expectedSourceError: SourceError{Origin: extraOrigin, Key: "values", Line: 2},
+			errorMatcher:        IsParsingError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClient := clientgofake.NewClientset()
+			if tc.values != nil {
+				k8sClient = clientgofake.NewClientset(
+					getConfigMapDefinition("test-extra-values", "giantswarm", tc.values),
+				)
+			}
+
+			v, err := New(Config{
+				K8sClient: k8sClient,
+				Logger:    microloggertest.New(),
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			_, err = v.MergeConfigMapData(context.Background(), app, v1alpha1.Catalog{})
+			if !tc.errorMatcher(err) {
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			var sourceErr *SourceError
+			if !errors.As(err, &sourceErr) {
+				t.Fatalf("error == %#v, want source error", err)
+			}
+
+			got := SourceError{Origin: sourceErr.Origin, Key: sourceErr.Key, Line: sourceErr.Line, Column: sourceErr.Column}
+			if got != tc.expectedSourceError {
+				t.Fatalf("want matching source error \n %s", cmp.Diff(got, tc.expectedSourceError, cmp.AllowUnexported(SourceError{})))
+			}
+		})
+	}
+}
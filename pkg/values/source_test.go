@@ -0,0 +1,165 @@
+package values
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+// testValueSource serves values from memory, keyed by namespace and name.
+type testValueSource struct {
+	data     map[string]map[string]string
+	isSecret bool
+}
+
+func (s *testValueSource) Get(ctx context.Context, name, namespace string) (map[string]string, error) {
+	data, ok := s.data[namespace+"/"+name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "test"}, name)
+	}
+
+	return data, nil
+}
+
+func (s *testValueSource) IsSecret() bool {
+	return s.isSecret
+}
+
+func Test_ValueSources(t *testing.T) {
+	tests := []struct {
+		name         string
+		extraConfigs []v1alpha1.AppExtraConfig
+		configMaps   []*corev1.ConfigMap
+		valueSources map[string]ValueSource
+		expectedData map[string]interface{}
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: custom kinds are merged in their chain by priority",
+			extraConfigs: []v1alpha1.AppExtraConfig{
+				{Kind: "inline", Name: "inline-values", Namespace: "giantswarm", Priority: v1alpha1.ConfigPriorityUser + 1},
+				{Kind: "vault", Name: "vault-values", Namespace: "giantswarm", Priority: v1alpha1.ConfigPriorityMaximum},
+				{Name: "extra-values", Namespace: "giantswarm", Priority: v1alpha1.ConfigPriorityMaximum},
+			},
+			configMaps: []*corev1.ConfigMap{
+				getConfigMapDefinition("extra-values", "giantswarm", map[string]string{
+					"values": "a: configmap\nb: configmap\n",
+				}),
+			},
+			valueSources: map[string]ValueSource{
+				"inline": &testValueSource{
+					data: map[string]map[string]string{
+						"giantswarm/inline-values": {"values": "a: inline\nb: inline\nc: inline\n"},
+					},
+				},
+				"vault": &testValueSource{
+					data: map[string]map[string]string{
+						"giantswarm/vault-values": {"values": "c: vault\n"},
+					},
+					isSecret: true,
+				},
+			},
+			expectedData: map[string]interface{}{
+				"a": "configmap",
+				"b": "configmap",
+				"c": "vault",
+			},
+		},
+		{
+			name: "case 1: built-in kinds can be replaced",
+			extraConfigs: []v1alpha1.AppExtraConfig{
+				{Name: "extra-values", Namespace: "giantswarm"},
+			},
+			configMaps: []*corev1.ConfigMap{
+				getConfigMapDefinition("extra-values", "giantswarm", map[string]string{
+					"values": "a: cluster\n",
+				}),
+			},
+			valueSources: map[string]ValueSource{
+				KindConfigMap: &testValueSource{
+					data: map[string]map[string]string{
+						"giantswarm/extra-values": {"values": "a: memory\n"},
+					},
+				},
+			},
+			expectedData: map[string]interface{}{
+				"a": "memory",
+			},
+		},
+		{
+			name: "case 2: unknown kinds are rejected",
+			extraConfigs: []v1alpha1.AppExtraConfig{
+				{Kind: "configmap", Name: "extra-values", Namespace: "giantswarm"},
+			},
+			errorMatcher: IsUnknownKind,
+		},
+		{
+			name: "case 3: not found errors of custom sources are recognized",
+			extraConfigs: []v1alpha1.AppExtraConfig{
+				{Kind: "vault", Name: "missing", Namespace: "giantswarm"},
+			},
+			valueSources: map[string]ValueSource{
+				"vault": &testValueSource{isSecret: true},
+			},
+			errorMatcher: IsNotFound,
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			objs := make([]runtime.Object, 0)
+			for _, cm := range tc.configMaps {
+				objs = append(objs, cm)
+			}
+
+			c := Config{
+				K8sClient:    clientgofake.NewClientset(objs...),
+				Logger:       microloggertest.New(),
+				ValueSources: tc.valueSources,
+			}
+			v, err := New(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			app := v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-test-app",
+					Namespace: "giantswarm",
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:      "test-catalog",
+					Name:         "test-app",
+					Namespace:    "giantswarm",
+					ExtraConfigs: tc.extraConfigs,
+				},
+			}
+
+			result, err := v.MergeAll(ctx, app, v1alpha1.Catalog{})
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.expectedData != nil && !reflect.DeepEqual(result, tc.expectedData) {
+				t.Fatalf("want matching data \n %s", cmp.Diff(result, tc.expectedData))
+			}
+		})
+	}
+}
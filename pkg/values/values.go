@@ -2,14 +2,16 @@ package values
 
 import (
 	"context"
-	"fmt"
+	"slices"
 
 	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
-	"github.com/imdario/mergo"
 	"k8s.io/client-go/kubernetes"
-	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultMaxConcurrentFetches = 5
 )
 
 // Config represents the configuration used to create a new values service.
@@ -17,6 +19,78 @@ type Config struct {
 	// Dependencies.
 	K8sClient kubernetes.Interface
 	Logger    micrologger.Logger
+
+	// Cache, when set, serves the data of all value sources from the given
+	// cache.
+	Cache *Cache
+	// DataKey, when set, is the only key values are read from in the data of
+	// configmaps and secrets. Other keys are ignored. It takes precedence
+	// over DataKeyMode.
+	DataKey string
+	// DataKeyMode defines how values are read from configmaps and secrets
+	// with several keys. It defaults to DataKeyModeSingle.
+	DataKeyMode string
+	// DetectSecretLeaks makes values not read from secrets be scanned for
+	// credentials, e.g. passwords, tokens and private keys. Findings are
+	// logged and returned by MergeConfigMapDataWithWarnings.
+	DetectSecretLeaks bool
+	// Interpolate makes placeholders like `${{ .ClusterID }}` in merged values
+	// be filled from the InterpolationContext of the app.
+	Interpolate bool
+	// Manifests makes values be read from the configmaps and secrets it
+	// holds instead of from the Kubernetes API. K8sClient is not needed
+	// then.
+	Manifests *Manifests
+	// MaxConcurrentFetches is the maximum number of configmaps and secrets
+	// read at the same time. It defaults to 5.
+	MaxConcurrentFetches int
+	// MaxDepth is the maximum nesting depth of the values of a single
+	// configmap or secret, counting maps and lists. Zero means no limit.
+	MaxDepth int
+	// MaxKeys is the maximum number of keys in the values of a single
+	// configmap or secret, counting the keys of nested maps. Zero means no
+	// limit.
+	MaxKeys int
+	// MaxSourceBytes is the maximum size of the data of a single configmap
+	// or secret. It is checked before the data is parsed. Zero means no
+	// limit.
+	MaxSourceBytes int
+	// MaxTotalBytes is the maximum size of the merged values encoded as
+	// JSON. Zero means no limit.
+	MaxTotalBytes int
+	// MergeStrategy defines how layers are merged on top of each other. By
+	// default lists are replaced and `null` values are kept. Extra configs
+	// can override it with the MergeStrategiesAnnotation on the app.
+	MergeStrategy MergeStrategy
+	// OptionalExtraConfigs lists extra configs by `<kind>/<namespace>/<name>`
+	// that are skipped instead of failing the merge when they do not exist.
+	// Apps can mark more with the OptionalExtraConfigsAnnotation.
+	OptionalExtraConfigs []string
+	// ReferenceNamespaces are the namespaces references may point to besides
+	// the namespace of the app CR.
+	ReferenceNamespaces []string
+	// ResolveReferences makes values like `valueFrom: {secretKeyRef: {name:
+	// x, key: y}}` be replaced by the value under the referenced key of the
+	// configmap or secret. Chained references are followed and cycles fail
+	// with invalidReferenceError. Secrets may only be referenced when values
+	// are merged with secrets, e.g. by MergeAll, MergeConfigMapData fails
+	// with invalidReferenceError on them.
+	ResolveReferences bool
+	// SecretChecksumKey makes MergeAllWithChecksums compute the checksum
+	// covering secret values as an HMAC-SHA-256 with this key. Without a key
+	// it is a plain SHA-256, which reveals short or guessable secrets to
+	// anyone hashing candidates, so it must then not be exposed to users not
+	// allowed to read the secrets.
+	SecretChecksumKey []byte
+	// SeparateSecretChecksum makes MergeAllWithChecksums hash the merged
+	// values read from configmaps and from secrets separately, so that the
+	// checksum of the configmap values can be shown without revealing
+	// anything about the secrets.
+	SeparateSecretChecksum bool
+	// ValueSources registers additional value sources by extra config kind.
+	// Sources registered for KindConfigMap or KindSecret replace the
+	// built-in ones.
+	ValueSources map[string]ValueSource
 }
 
 // Values implements the values service.
@@ -24,21 +98,127 @@ type Values struct {
 	// Dependencies.
 	k8sClient kubernetes.Interface
 	logger    micrologger.Logger
+
+	cache                   *Cache
+	dataKey                 string
+	dataKeyMode             string
+	detectSecretLeaks       bool
+	interpolation           bool
+	maxConcurrentFetches    int
+	maxDepth                int
+	maxKeys                 int
+	maxSourceBytes          int
+	maxTotalBytes           int
+	mergeStrategy           MergeStrategy
+	optionalExtraConfigKeys []string
+	referenceNamespaces     []string
+	references              bool
+	secretChecksumKey       []byte
+	separateSecretChecksum  bool
+	valueSources            map[string]ValueSource
 }
 
 // New creates a new configured values service.
 func New(config Config) (*Values, error) {
-	if config.K8sClient == nil {
+	if config.K8sClient == nil && config.Manifests == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
 	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
 
+	var valueSources map[string]ValueSource
+	if config.Manifests != nil {
+		valueSources = map[string]ValueSource{
+			KindConfigMap: &manifestSource{
+				logger:    config.Logger,
+				manifests: config.Manifests,
+			},
+			KindSecret: &manifestSource{
+				logger:    config.Logger,
+				manifests: config.Manifests,
+
+				isSecret: true,
+			},
+		}
+	} else {
+		valueSources = map[string]ValueSource{
+			KindConfigMap: &configMapSource{
+				k8sClient: config.K8sClient,
+				logger:    config.Logger,
+			},
+			KindSecret: &secretSource{
+				k8sClient: config.K8sClient,
+				logger:    config.Logger,
+			},
+		}
+	}
+	for kind, source := range config.ValueSources {
+		if source == nil {
+			return nil, microerror.Maskf(invalidConfigError, "%T.ValueSources[%#q] must not be empty", config, kind)
+		}
+
+		valueSources[kind] = source
+	}
+
+	if config.Cache != nil {
+		for kind, source := range valueSources {
+			valueSources[kind] = &cachedSource{
+				cache:  config.Cache,
+				kind:   kind,
+				source: source,
+			}
+		}
+	}
+
+	maxConcurrentFetches := config.MaxConcurrentFetches
+	if maxConcurrentFetches == 0 {
+		maxConcurrentFetches = defaultMaxConcurrentFetches
+	}
+	if maxConcurrentFetches < 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.MaxConcurrentFetches must not be negative", config)
+	}
+
+	if config.MaxDepth < 0 || config.MaxKeys < 0 || config.MaxSourceBytes < 0 || config.MaxTotalBytes < 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.MaxDepth, %T.MaxKeys, %T.MaxSourceBytes and %T.MaxTotalBytes must not be negative", config, config, config, config)
+	}
+
+	dataKeyMode := config.DataKeyMode
+	switch dataKeyMode {
+	case "":
+		dataKeyMode = DataKeyModeSingle
+	case DataKeyModeSingle, DataKeyModeMerge, DataKeyModeNested:
+	default:
+		return nil, microerror.Maskf(invalidConfigError, "%T.DataKeyMode must be one of %#q, %#q or %#q", config, DataKeyModeSingle, DataKeyModeMerge, DataKeyModeNested)
+	}
+
+	err := config.MergeStrategy.validate()
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.MergeStrategy is invalid, logs: %s", config, err.Error())
+	}
+
 	r := &Values{
 		// Dependencies.
 		k8sClient: config.K8sClient,
 		logger:    config.Logger,
+
+		cache:                   config.Cache,
+		dataKey:                 config.DataKey,
+		dataKeyMode:             dataKeyMode,
+		detectSecretLeaks:       config.DetectSecretLeaks,
+		interpolation:           config.Interpolate,
+		maxConcurrentFetches:    maxConcurrentFetches,
+		maxDepth:                config.MaxDepth,
+		maxKeys:                 config.MaxKeys,
+		maxSourceBytes:          config.MaxSourceBytes,
+		maxTotalBytes:           config.MaxTotalBytes,
+		mergeStrategy:           config.MergeStrategy,
+		optionalExtraConfigKeys: config.OptionalExtraConfigs,
+		referenceNamespaces:     config.ReferenceNamespaces,
+		references:              config.ResolveReferences,
+		secretChecksumKey:       config.SecretChecksumKey,
+		separateSecretChecksum:  config.SeparateSecretChecksum,
+		valueSources:            valueSources,
 	}
 
 	return r, nil
@@ -47,106 +227,106 @@ func New(config Config) (*Values, error) {
 // MergeAll merges both configmap and secret values to produce a single set of
 // values that can be passed to Helm.
 func (v *Values) MergeAll(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (map[string]interface{}, error) {
-	configMapData, err := v.MergeConfigMapData(ctx, app, catalog)
+	data, err := v.mergeAll(ctx, app, catalog, nil, nil, nil)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	secretData, err := v.MergeSecretData(ctx, app, catalog)
-	if err != nil {
-		return nil, microerror.Mask(err)
-	}
+	return data, nil
+}
+
+// MergeAllWithProvenance works like MergeAll but also returns the provenance
+// of every merged value.
+func (v *Values) MergeAllWithProvenance(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog) (map[string]interface{}, *Provenance, error) {
+	provenance := NewProvenance()
 
-	err = mergo.Merge(&configMapData, secretData, mergo.WithOverride)
+	data, err := v.mergeAll(ctx, app, catalog, provenance, nil, nil)
 	if err != nil {
-		return nil, microerror.Mask(err)
+		return nil, nil, microerror.Mask(err)
 	}
 
-	return configMapData, nil
+	return data, provenance, nil
 }
 
-func extractData(resourceType, name string, data map[string]string) (map[string]interface{}, error) {
-	var err error
-	var rawMapData map[string]interface{}
-
-	if data == nil {
-		return rawMapData, nil
+// mergeAll merges the configmap and secret values. When provenance is not nil
+// the origin of every merged value is recorded in it, when checksums is not
+// nil the checksums of the values are set in it and when warnings is not nil
+// the warnings found are appended to it.
+func (v *Values) mergeAll(ctx context.Context, app v1alpha1.App, catalog v1alpha1.Catalog, provenance *Provenance, checksums *Checksums, warnings *[]Warning) (map[string]interface{}, error) {
+	// Separate checksums split the merged values by their provenance.
+	if checksums != nil && v.separateSecretChecksum && provenance == nil {
+		provenance = NewProvenance()
 	}
 
-	if len(data) != 1 {
-		return nil, microerror.Maskf(parsingError, "expected %#q %s has only one key but got %d", name, resourceType, len(data))
+	configMapOrigins, err := v.configMapOrigins(app, catalog)
+	if err != nil {
+		return nil, microerror.Mask(err)
 	}
 
-	var rawData []byte
-	for _, v := range data {
-		rawData = []byte(v)
+	secretOrigins, err := v.secretOrigins(app, catalog)
+	if err != nil {
+		return nil, microerror.Mask(err)
 	}
 
-	err = yaml.Unmarshal(rawData, &rawMapData)
+	// All layers are fetched at once so that configmaps and secrets are
+	// read concurrently. They are merged in order afterwards.
+	layers, err := v.fetchLayers(ctx, app, slices.Concat(configMapOrigins, secretOrigins))
 	if err != nil {
-		return nil, microerror.Maskf(parsingError, "failed to parse %#q %s, logs: %s", name, resourceType, err.Error())
+		return nil, microerror.Mask(err)
 	}
 
-	return rawMapData, nil
-}
+	leaks := v.scanForSecretLeaks(ctx, layers[:len(configMapOrigins)])
+	if warnings != nil {
+		*warnings = append(*warnings, leaks...)
+		*warnings = append(*warnings, skippedLayers(layers)...)
+	}
 
-func extractNonNestedData(data map[string]string) (map[string]interface{}, error) {
-	var err error
-	var rawMapData map[string]interface{}
+	data, err := v.mergeLayers(ctx, layers[:len(configMapOrigins)], provenance)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
 
-	if data == nil {
-		return rawMapData, nil
+	// Secret values are merged layer by layer on top of the configmap
+	// values, so that every secret extra config is merged with its own
+	// strategy.
+	if data == nil && len(secretOrigins) > 0 {
+		data = map[string]interface{}{}
+	}
+	for _, l := range layers[len(configMapOrigins):] {
+		err = v.mergeLayer(ctx, data, l, provenance)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
 	}
 
-	var rawData []byte
-	for _, v := range data {
-		rawData = []byte(v)
+	pruneDeleted(data)
+	if provenance != nil {
+		provenance.pruneDeleted()
 	}
 
-	err = yaml.Unmarshal(rawData, &rawMapData)
+	err = v.interpolate(app, data)
 	if err != nil {
-		return nil, err
+		return nil, microerror.Mask(err)
 	}
 
-	return rawMapData, nil
-}
-
-// fetchAndMergeExtraConfigs loops the given list of extra configs, fetches their data with the given method
-// and merges them into the given destination, modifying it inplace
-func (v *Values) fetchAndMergeExtraConfigs(
-	ctx context.Context,
-	extraConfigs []v1alpha1.AppExtraConfig,
-	dataFetcherMethod func(ctx context.Context, name, namespace string) (map[string]string, error),
-	destinationData map[string]interface{},
-) error {
-	v.logger.LogCtx(ctx, "level", "debug", "message", "checking next layer of extra configs...")
-
-	for _, entry := range extraConfigs {
-		rawData, err := dataFetcherMethod(ctx, entry.Name, entry.Namespace)
-		if err != nil {
-			return microerror.Mask(err)
-		}
+	err = v.resolveReferences(ctx, app, data, provenance, true)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
 
-		data, err := extractNonNestedData(rawData)
-		if err != nil {
-			return microerror.Maskf(parsingError, "failed to parse %#q in %#q, logs: %s", entry.Name, entry.Namespace, err.Error())
-		}
+	err = v.checkTotalSize(app, data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
 
-		err = mergo.Merge(&destinationData, data, mergo.WithOverride)
+	if checksums != nil {
+		err = v.setChecksums(checksums, data, provenance)
 		if err != nil {
-			return microerror.Mask(err)
+			return nil, microerror.Mask(err)
 		}
-
-		v.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf(
-			"merged %#q in %#q of kind %#q and priority %d", entry.Name, entry.Namespace, entry.Kind, entry.Priority,
-		))
 	}
 
-	v.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf(
-		"finished merging %d extra config(s) in this extra configs layer", len(extraConfigs),
-	))
-
-	return nil
+	return data, nil
 }
 
 // toStringMap converts from a byte slice map to a string map.
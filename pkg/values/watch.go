@@ -0,0 +1,236 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/giantswarm/app/v8/pkg/key"
+)
+
+// SourceKey identifies an object values are read from.
+type SourceKey struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+func (k SourceKey) String() string {
+	return fmt.Sprintf("%s/%s/%s", k.Kind, k.Namespace, k.Name)
+}
+
+// SourceKeys returns the keys of all configmaps, secrets and objects of other
+// registered kinds the values of the given app are read from, following the
+// same rules as MergeAll. Optional extra configs are included even when they
+// do not exist. Objects only read through references, see
+// Config.ResolveReferences, are not included. Keys are sorted and unique.
+func (v *Values) SourceKeys(app v1alpha1.App, catalog v1alpha1.Catalog) ([]SourceKey, error) {
+	configMapOrigins, err := v.configMapOrigins(app, catalog)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	secretOrigins, err := v.secretOrigins(app, catalog)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var keys []SourceKey
+	for _, o := range slices.Concat(configMapOrigins, secretOrigins) {
+		keys = append(keys, SourceKey{
+			Kind:      o.Kind,
+			Namespace: o.Namespace,
+			Name:      o.Name,
+		})
+	}
+
+	slices.SortFunc(keys, func(a, b SourceKey) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return slices.Compact(keys), nil
+}
+
+// SourceIndexField is the field index IndexField registers for Apps and
+// Catalogs. Apps are indexed by the keys of the objects their values are read
+// from and by the keys of the catalogs they may use, Catalogs by the keys of
+// the objects their values are read from.
+const SourceIndexField = "values.giantswarm.io/source"
+
+// kindCatalog is the kind of SourceKey identifying a catalog in the
+// SourceIndexField of Apps.
+const kindCatalog = "catalog"
+
+// IndexField registers SourceIndexField for Apps and Catalogs with the given
+// indexer, e.g. the field indexer of a controller-runtime manager. It must be
+// called before SourceEventHandler is used with a client backed by the cache
+// the indexer belongs to.
+func (v *Values) IndexField(ctx context.Context, indexer client.FieldIndexer) error {
+	err := indexer.IndexField(ctx, &v1alpha1.App{}, SourceIndexField, v.appSourceIndex)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = indexer.IndexField(ctx, &v1alpha1.Catalog{}, SourceIndexField, v.catalogSourceIndex)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// appSourceIndex returns the SourceIndexField values of the given App: the
+// keys of the objects its values are read from, without those of its
+// catalog, and the keys of the catalogs it may use.
+func (v *Values) appSourceIndex(obj client.Object) []string {
+	app, ok := obj.(*v1alpha1.App)
+	if !ok {
+		return nil
+	}
+
+	keys, err := v.SourceKeys(*app, v1alpha1.Catalog{})
+	if err != nil {
+		// Apps with invalid annotations cannot be resolved anyway.
+		v.logger.LogCtx(context.Background(), "level", "debug", "message", fmt.Sprintf("skipped indexing app %#q in namespace %#q", app.Name, app.Namespace), "stack", microerror.JSON(err))
+		return nil
+	}
+
+	if key.CatalogName(*app) != "" {
+		// Catalogs without a namespace are looked up in the default and
+		// giantswarm namespaces, like the validation does.
+		namespaces := []string{metav1.NamespaceDefault, "giantswarm"}
+		if key.CatalogNamespace(*app) != "" {
+			namespaces = []string{key.CatalogNamespace(*app)}
+		}
+
+		for _, ns := range namespaces {
+			keys = append(keys, SourceKey{Kind: kindCatalog, Namespace: ns, Name: key.CatalogName(*app)})
+		}
+	}
+
+	return sourceKeyStrings(keys)
+}
+
+// catalogSourceIndex returns the SourceIndexField values of the given
+// Catalog: the keys of the objects its values are read from.
+func (v *Values) catalogSourceIndex(obj client.Object) []string {
+	catalog, ok := obj.(*v1alpha1.Catalog)
+	if !ok {
+		return nil
+	}
+
+	keys, err := v.SourceKeys(v1alpha1.App{}, *catalog)
+	if err != nil {
+		return nil
+	}
+
+	return sourceKeyStrings(keys)
+}
+
+func sourceKeyStrings(keys []SourceKey) []string {
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, k.String())
+	}
+
+	return values
+}
+
+// SourceEventHandler returns a controller-runtime event handler for
+// configmaps and secrets that enqueues every App whose values are read from
+// the changed object, e.g.
+//
+//	err := v.IndexField(ctx, mgr.GetFieldIndexer())
+//	...
+//	Watches(&corev1.ConfigMap{}, v.SourceEventHandler(mgr.GetClient()))
+//
+// Apps and Catalogs are listed by SourceIndexField with the given client,
+// which must be backed by the cache IndexField registered the index with.
+// When Config.Cache is set the cached data of the changed object is
+// invalidated before Apps are enqueued, so that they read the new data.
+func (v *Values) SourceEventHandler(ctrlClient client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		requests, err := v.appsForSource(ctx, ctrlClient, obj)
+		if err != nil {
+			v.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("failed to find apps reading values from %#q in namespace %#q", obj.GetName(), obj.GetNamespace()), "stack", microerror.JSON(err))
+			return nil
+		}
+
+		return requests
+	})
+}
+
+// appsForSource returns a request for every App whose values are read from
+// the given configmap or secret, either directly or through its catalog.
+func (v *Values) appsForSource(ctx context.Context, ctrlClient client.Client, obj client.Object) ([]reconcile.Request, error) {
+	var kind string
+	switch obj.(type) {
+	case *corev1.ConfigMap:
+		kind = KindConfigMap
+	case *corev1.Secret:
+		kind = KindSecret
+	default:
+		return nil, nil
+	}
+
+	source := SourceKey{
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+
+	if v.cache != nil {
+		v.cache.Invalidate(source.Kind, source.Namespace, source.Name)
+	}
+
+	var apps v1alpha1.AppList
+	err := ctrlClient.List(ctx, &apps, client.MatchingFields{SourceIndexField: source.String()})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	// Only few objects are read by catalogs, so this mostly lists nothing.
+	var catalogs v1alpha1.CatalogList
+	err = ctrlClient.List(ctx, &catalogs, client.MatchingFields{SourceIndexField: source.String()})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	for _, catalog := range catalogs.Items {
+		catalogKey := SourceKey{Kind: kindCatalog, Namespace: catalog.Namespace, Name: catalog.Name}
+
+		var catalogApps v1alpha1.AppList
+		err = ctrlClient.List(ctx, &catalogApps, client.MatchingFields{SourceIndexField: catalogKey.String()})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		apps.Items = append(apps.Items, catalogApps.Items...)
+	}
+
+	var requests []reconcile.Request
+	for _, app := range apps.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: app.Namespace,
+				Name:      app.Name,
+			},
+		})
+	}
+
+	slices.SortFunc(requests, func(a, b reconcile.Request) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return slices.Compact(requests), nil
+}
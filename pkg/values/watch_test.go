@@ -0,0 +1,210 @@
+package values
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions-application/api/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake" //nolint:staticcheck
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_SourceKeys(t *testing.T) {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-test-app",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "test-catalog",
+			Name:      "test-app",
+			Namespace: "giantswarm",
+			Config: v1alpha1.AppSpecConfig{
+				ConfigMap: v1alpha1.AppSpecConfigConfigMap{
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+				},
+			},
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				Secret: v1alpha1.AppSpecUserConfigSecret{
+					Name:      "test-user-secrets",
+					Namespace: "giantswarm",
+				},
+			},
+			ExtraConfigs: []v1alpha1.AppExtraConfig{
+				{
+					Kind:      "configMap",
+					Name:      "test-cluster-values",
+					Namespace: "giantswarm",
+					Priority:  120,
+				},
+				{
+					Kind:      "secret",
+					Name:      "test-extra-secrets",
+					Namespace: "org-acme",
+				},
+			},
+		},
+	}
+
+	catalog := v1alpha1.Catalog{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-catalog",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.CatalogSpec{
+			Config: &v1alpha1.CatalogSpecConfig{
+				ConfigMap: &v1alpha1.CatalogSpecConfigConfigMap{
+					Name:      "test-catalog-values",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	v, err := New(Config{
+		K8sClient: clientgofake.NewClientset(),
+		Logger:    microloggertest.New(),
+	})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	keys, err := v.SourceKeys(app, catalog)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	expectedKeys := []SourceKey{
+		{Kind: KindConfigMap, Namespace: "giantswarm", Name: "test-catalog-values"},
+		{Kind: KindConfigMap, Namespace: "giantswarm", Name: "test-cluster-values"},
+		{Kind: KindSecret, Namespace: "giantswarm", Name: "test-user-secrets"},
+		{Kind: KindSecret, Namespace: "org-acme", Name: "test-extra-secrets"},
+	}
+	if !reflect.DeepEqual(keys, expectedKeys) {
+		t.Fatalf("want matching keys \n %s", cmp.Diff(keys, expectedKeys))
+	}
+}
+
+func Test_SourceEventHandler(t *testing.T) {
+	catalog := &v1alpha1.Catalog{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-catalog",
+			Namespace: "giantswarm",
+		},
+		Spec: v1alpha1.CatalogSpec{
+			Config: &v1alpha1.CatalogSpecConfig{
+				ConfigMap: &v1alpha1.CatalogSpecConfigConfigMap{
+					Name:      "test-catalog-values",
+					Namespace: "giantswarm",
+				},
+			},
+		},
+	}
+
+	newApp := func(name, userConfigMap string) *v1alpha1.App {
+		return &v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "org-acme",
+			},
+			Spec: v1alpha1.AppSpec{
+				Catalog:   "test-catalog",
+				Name:      "test-app",
+				Namespace: "giantswarm",
+				UserConfig: v1alpha1.AppSpecUserConfig{
+					ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+						Name:      userConfigMap,
+						Namespace: "org-acme",
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		obj               client.Object
+		expectedRequests  []reconcile.Request
+		expectedCacheSize int
+	}{
+		{
+			name: "case 0: catalog configmap enqueues all apps of the catalog",
+			obj:  &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-catalog-values", Namespace: "giantswarm"}},
+			expectedRequests: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "org-acme", Name: "app-a"}},
+				{NamespacedName: types.NamespacedName{Namespace: "org-acme", Name: "app-b"}},
+			},
+		},
+		{
+			name: "case 1: user configmap enqueues its app only",
+			obj:  &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-b-user-values", Namespace: "org-acme"}},
+			expectedRequests: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "org-acme", Name: "app-b"}},
+			},
+		},
+		{
+			name: "case 2: secret with the name of a configmap enqueues nothing",
+			obj:  &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "app-b-user-values", Namespace: "org-acme"}},
+			// The configmap with the same name stays cached.
+			expectedCacheSize: 1,
+		},
+		{
+			name: "case 3: unrelated configmap enqueues nothing",
+			obj:  &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "org-acme"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cache, err := NewCache(CacheConfig{TTL: time.Minute})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+			cache.set(cacheKey{kind: KindConfigMap, namespace: tc.obj.GetNamespace(), name: tc.obj.GetName()}, map[string]string{})
+
+			v, err := New(Config{
+				K8sClient: clientgofake.NewClientset(),
+				Logger:    microloggertest.New(),
+				Cache:     cache,
+			})
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			scheme := runtime.NewScheme()
+			_ = v1alpha1.AddToScheme(scheme)
+
+			ctrlClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(catalog, newApp("app-a", "app-a-user-values"), newApp("app-b", "app-b-user-values")).
+				WithIndex(&v1alpha1.App{}, SourceIndexField, v.appSourceIndex).
+				WithIndex(&v1alpha1.Catalog{}, SourceIndexField, v.catalogSourceIndex).
+				Build()
+
+			requests, err := v.appsForSource(context.Background(), ctrlClient, tc.obj)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			if !reflect.DeepEqual(requests, tc.expectedRequests) {
+				t.Fatalf("want matching requests \n %s", cmp.Diff(requests, tc.expectedRequests))
+			}
+
+			// Changed configmaps must not be served from the cache.
+			if size := cache.Stats().Size; size != tc.expectedCacheSize {
+				t.Fatalf("cache size == %d, want %d", size, tc.expectedCacheSize)
+			}
+		})
+	}
+}